@@ -9,92 +9,21 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
+// OLLAMA_MODELS, OPENAI_MODELS, GEMINI_MODELS and OPENROUTER_MODELS are
+// populated at startup from utils.FetchModelCatalog (a signed remote
+// manifest, falling back to utils.BundledModelCatalog when offline or
+// unreachable) instead of being hard-coded here.
 var (
-	// https://github.com/andthattoo/ollama-workflows/edit/main/src/program/models.rs#L14
-	OLLAMA_MODELS = []string{
-		"finalend/hermes-3-llama-3.1:8b-q8_0",
-
-		"phi3:14b-medium-4k-instruct-q4_1",
-		"phi3:14b-medium-128k-instruct-q4_1",
-
-		"phi3.5:3.8b",
-		"phi3.5:3.8b-mini-instruct-fp16",
-
-		"gemma2:9b-instruct-q8_0",
-		"gemma2:9b-instruct-fp16",
-
-		"llama3.1:latest",
-		"llama3.1:8b-instruct-q8_0",
-		"llama3.1:8b-instruct-fp16",
-		"llama3.1:8b-text-q4_K_M",
-		"llama3.1:8b-text-q8_0",
-		"llama3.1:70b-instruct-q4_0",
-		"llama3.1:70b-instruct-q8_0",
-		"llama3.1:70b-text-q4_0",
-		"llama3.3:70b",
-		"llama3.2:1b",
-		"llama3.2:1b-text-q4_K_M",
-		"llama3.2:3b",
-
-		"qwen2.5:7b-instruct-q5_0",
-		"qwen2.5:7b-instruct-fp16",
-		"qwen2.5:32b-instruct-fp16",
-		"qwen2.5-coder:1.5b",
-		"qwen2.5-coder:7b-instruct",
-		"qwen2.5-coder:7b-instruct-q8_0",
-		"qwen2.5-coder:7b-instruct-fp16",
-		"qwq",
-
-		"deepseek-coder:6.7b",
-
-		"mixtral:8x7b",
-	}
-	// https://github.com/andthattoo/ollama-workflows/edit/main/src/program/models.rs#L76
-	OPENAI_MODELS = []string{
-		"gpt-4-turbo",
-		"gpt-4o",
-		"gpt-4o-mini",
-
-		"o1-mini",
-		"o1-preview",
-	}
-
-	GEMINI_MODELS = []string{
-		"gemini-1.0-pro",
-
-		"gemini-1.5-pro",
-		"gemini-1.5-pro-exp-0827",
-		"gemini-1.5-flash",
-		"gemini-2.0-flash-exp",
-
-		"gemma-2-2b-it",
-		"gemma-2-9b-it",
-		"gemma-2-27b-it",
-	}
-
-	OPENROUTER_MODELS = []string{
-		"meta-llama/llama-3.1-8b-instruct",
-		"meta-llama/llama-3.1-70b-instruct",
-		"meta-llama/llama-3.1-405b-instruct",
-		"meta-llama/llama-3.1-70b-instruct:free",
-		"meta-llama/llama-3.3-70b-instruct",
-
-		"anthropic/claude-3.5-sonnet:beta",
-		"anthropic/claude-3-5-haiku-20241022:beta",
-
-		"qwen/qwen-2.5-72b-instruct",
-		"qwen/qwen-2.5-7b-instruct",
-		"qwen/qwen-2.5-coder-32b-instruct",
-		"qwen/qwq-32b-preview",
-
-		"deepseek/deepseek-chat",
-		"nousresearch/hermes-3-llama-3.1-405b",
-		"nvidia/llama-3.1-nemotron-70b-instruct",
-	}
+	OLLAMA_MODELS     []string
+	OPENAI_MODELS     []string
+	GEMINI_MODELS     []string
+	OPENROUTER_MODELS []string
 
 	// Default admin public key, it will be used unless --dkn-admin-public-key is given
 	DKN_ADMIN_PUBLIC_KEY = "0208ef5e65a9c656a6f92fb2c770d5d5e2ecffe02a6aade19207f75110be6ae658"
@@ -123,6 +52,26 @@ var version = "dev"
 //  4. Starts the compute node, either in foreground or background mode.
 //  5. Handles graceful shutdown in foreground mode by capturing interrupt signals.
 func main() {
+	// "launcher daemon" runs the long-running supervisor with a local control
+	// API instead of the regular single-node foreground/background flow.
+	if len(os.Args) > 1 && os.Args[1] == "daemon" {
+		runDaemonCommand(os.Args[2:])
+		return
+	}
+
+	// "launcher submit|list|status|stream|stop" are thin clients that talk to
+	// an already-running `launcher daemon` over its IPC socket instead of
+	// running a node themselves.
+	daemonClientActions := map[string]bool{"submit": true, "list": true, "status": true, "stream": true, "stop": true}
+	if len(os.Args) > 1 && daemonClientActions[os.Args[1]] {
+		runDaemonClientCommand(os.Args[1], os.Args[2:])
+		return
+	}
+
+	// install SIGTERM/SIGINT/SIGHUP handlers so Ctrl-C and friends stop every
+	// dkn-compute process we spawn instead of leaving them orphaned
+	go utils.DefaultDeathWatcher.Watch()
+
 	logger := log.New(os.Stdout, "[DKN-COMPUTE-LAUNCHER] ", log.Ldate|log.Ltime)
 	launcherVersion, err := utils.GetLauncherLatestVersion()
 	if err != nil {
@@ -134,6 +83,18 @@ func main() {
 
 	fmt.Println("************ DKN - Compute Node ************")
 
+	accelKind, accelDetails := utils.DetectAcceleration()
+	switch accelKind {
+	case utils.AccelerationNvidia:
+		fmt.Printf("Acceleration: NVIDIA GPU detected (%s)\n", accelDetails["gpu"])
+	case utils.AccelerationROCm:
+		fmt.Printf("Acceleration: AMD ROCm GPU detected (%s)\n", accelDetails["gpu"])
+	case utils.AccelerationMetal:
+		fmt.Printf("Acceleration: Apple Metal (Apple Silicon)\n")
+	default:
+		fmt.Printf("Acceleration: none detected, Ollama will run on CPU (this will be slow for large models)\n")
+	}
+
 	help := flag.Bool("h", false, "Displays this help message")
 	flag.BoolVar(help, "help", false, "Displays this help message")
 	var models utils.ModelList
@@ -146,6 +107,20 @@ func main() {
 	dkn_admin_pkey_flag := flag.String("dkn-admin-public-key", DKN_ADMIN_PUBLIC_KEY, "DKN Admin Node Public Key, usually dont need this since it's given by default")
 	pick_model := flag.Bool("pick-models", false, "Pick the models using cli, supprases the -m flags (default: false)")
 	use_compute_dev_version := flag.Bool("compute-dev-version", false, "For using the latest dev version of dkn-compute node (optional, only for development purposes)")
+	group := flag.String("group", "", "Path to a TOML task-group config describing multiple dkn-compute tasks to run in parallel")
+	watch := flag.Bool("watch", false, "Watch .env and models.toml for changes and hot-reload the node on the fly (foreground mode only)")
+	non_interactive := flag.Bool("non-interactive", false, "Disable interactive prompts; required but missing API keys become hard errors instead (useful with --watch)")
+	docker := flag.Bool("docker", false, "Run dkn-compute via a generated docker-compose.yml instead of the native binary")
+	pull := flag.Bool("pull", false, "Force a `docker compose pull` before bringing up the --docker deployment")
+	platform := flag.String("platform", "", "Image platform for --docker, e.g. linux/amd64 or linux/arm64 (default: let Docker decide)")
+	registry := flag.String("registry", "", "Image registry override for --docker (default: ghcr.io/firstbatchxyz)")
+	models_manifest := flag.String("models-manifest", "", "URL of the signed model catalog manifest (default: utils.DefaultModelsManifestURL)")
+	offline := flag.Bool("offline", false, "Skip fetching the model catalog manifest and use the bundled model lists")
+	insecure_skip_verify := flag.Bool("insecure-skip-verify", false, "Skip SHA-256/signature verification of downloaded dkn-compute binaries (not recommended)")
+	require_verification := flag.Bool("require-verification", false, "Fail closed if a dkn-compute binary's checksum/signature companion file is missing, instead of warning and continuing (recommended for unattended/background deployments)")
+	models_profile := flag.String("models-profile", "", "Path to a YAML/JSON file listing models to run per provider, for non-interactive startup without a TTY")
+	secret_source := flag.String("secret-source", "", "Where to read the DKN Wallet Secret Key from: keyring|env|file|stdin (default: try env, keyring, file, then stdin)")
+	ollama_pool_status_addr := flag.String("ollama-pool-status-addr", "", "Address (e.g. 127.0.0.1:7777) for a local HTTP endpoint reporting Ollama pool health/routing state, for debugging (default: disabled)")
 	flag.Parse()
 
 	// Display help and exit if -h or --help is provided
@@ -168,16 +143,38 @@ func main() {
 
 	// override DKN_ADMIN_PUBLIC_KEY if flag is a different value
 	DKN_ADMIN_PUBLIC_KEY = *dkn_admin_pkey_flag
-	utils.CheckRequiredEnvVars(&envvars, DKN_ADMIN_PUBLIC_KEY)
+	utils.CheckRequiredEnvVars(&envvars, DKN_ADMIN_PUBLIC_KEY, *secret_source, working_dir)
+	utils.RequireComputeBinaryVerification = *require_verification
+
+	// resolve the model catalog: a signed remote manifest by default, the
+	// bundled fallback copy when --offline is given or the fetch/verification fails
+	modelCatalog, err := utils.FetchModelCatalog(working_dir, *models_manifest, *offline, DKN_ADMIN_PUBLIC_KEY)
+	if err != nil {
+		fmt.Printf("Warning: %s\n", err)
+	}
+	OPENAI_MODELS = modelCatalog.OpenAI
+	GEMINI_MODELS = modelCatalog.Gemini
+	OPENROUTER_MODELS = modelCatalog.OpenRouter
+	OLLAMA_MODELS = modelCatalog.Ollama
 
 	// if -m flag is given, set them as DKN_MODELS
 	if len(models) != 0 {
 		envvars["DKN_MODELS"] = strings.Join(models, ",")
 	}
 
-	// if DKN_MODELS are still empty, pick model interactively
+	// if DKN_MODELS are still empty, pick a model, either from a profile file
+	// (non-interactive, for CI/systemd) or interactively
 	if envvars["DKN_MODELS"] == "" || *pick_model {
-		pickedModels := utils.PickModels(OPENAI_MODELS, GEMINI_MODELS, OPENROUTER_MODELS, OLLAMA_MODELS)
+		var pickedModels string
+		if *models_profile != "" {
+			pickedModels, err = utils.PickModelsFromProfile(*models_profile, envvars["OPENAI_API_KEY"], envvars["GEMINI_API_KEY"])
+			if err != nil {
+				fmt.Println(err)
+				utils.ExitWithDelay(1)
+			}
+		} else {
+			pickedModels = utils.PickModels(OPENAI_MODELS, GEMINI_MODELS, OPENROUTER_MODELS, OLLAMA_MODELS)
+		}
 		if pickedModels == "" {
 			fmt.Println("No valid model picked")
 			utils.ExitWithDelay(1)
@@ -216,11 +213,51 @@ func main() {
 
 	// check ollama environment
 	if utils.IsOllamaRequired(envvars["DKN_MODELS"], &OLLAMA_MODELS) {
-		ollamaHost, ollamaPort := utils.HandleOllamaEnv(envvars["OLLAMA_HOST"], envvars["OLLAMA_PORT"])
-		envvars["OLLAMA_HOST"] = ollamaHost
-		envvars["OLLAMA_PORT"] = ollamaPort
+		pickedOllamaModels := pickedModelsOf(envvars["DKN_MODELS"], OLLAMA_MODELS)
+
+		if ollamaHosts := utils.ParseOllamaHosts(envvars["OLLAMA_HOSTS"]); len(ollamaHosts) > 0 {
+			// multiple Ollama backends given, route to whichever healthy one
+			// hosts the picked model instead of assuming a single local instance
+			ollamaPool := utils.NewOllamaPool(ollamaHosts, "")
+			ollamaPool.RefreshAll()
+			ollamaPool.Start(30*time.Second, make(chan struct{}))
+
+			if *ollama_pool_status_addr != "" {
+				go func() {
+					if err := ollamaPool.ServeStatus(*ollama_pool_status_addr); err != nil {
+						fmt.Printf("Warning: ollama pool status server on %s stopped: %s\n", *ollama_pool_status_addr, err)
+					}
+				}()
+			}
+
+			model := ""
+			if len(pickedOllamaModels) > 0 {
+				model = pickedOllamaModels[0]
+			}
+			endpoint, err := ollamaPool.Pick(model, nil)
+			if err != nil {
+				fmt.Println(err)
+				utils.ExitWithDelay(1)
+			}
+			envvars["OLLAMA_HOST"] = endpoint
+			envvars["OLLAMA_PORT"] = ""
+
+			fmt.Printf("Ollama host (pooled): %s\n\n", endpoint)
+		} else {
+			ollamaHost, ollamaPort := utils.HandleOllamaEnv(envvars["OLLAMA_HOST"], envvars["OLLAMA_PORT"])
+			envvars["OLLAMA_HOST"] = ollamaHost
+			envvars["OLLAMA_PORT"] = ollamaPort
+
+			fmt.Printf("Ollama host: %s\n\n", envvars["OLLAMA_HOST"])
 
-		fmt.Printf("Ollama host: %s\n\n", envvars["OLLAMA_HOST"])
+			// preload the picked Ollama models now, instead of letting the first
+			// inference request block for minutes on an on-demand pull
+			if len(pickedOllamaModels) > 0 {
+				if err := utils.EnsureOllamaModels(ollamaHost, ollamaPort, pickedOllamaModels); err != nil {
+					fmt.Printf("Warning: %s\n", err)
+				}
+			}
+		}
 	} else {
 		fmt.Printf("No Ollama model provided. Skipping the Ollama execution\n\n")
 	}
@@ -244,19 +281,26 @@ func main() {
 	}
 
 	// get latest dkn_compute binary version
-	computeVersion, err := utils.GetComputeVersionTag(!(*use_compute_dev_version), *use_compute_dev_version, false)
+	computeVersion, err := utils.GetComputeLatestTag(!(*use_compute_dev_version), *use_compute_dev_version, false)
 	if err != nil {
 		fmt.Println("Couldn't get the latest dkn-compute version")
 		utils.ExitWithDelay(1)
 	}
 	dkn_compute_binary := utils.ComputeBinaryFileName()
 
+	// --docker: deploy via a generated docker-compose.yml instead of downloading
+	// and running the native binary directly.
+	if *docker {
+		runDockerCommand(working_dir, computeVersion, envvars, *platform, *registry, *pull)
+		return
+	}
+
 	// check dkn-compute binary has already installed
 	if utils.FileExists(utils.ComputeBinaryFileName()) {
 		// compare current and latest versions
 		if computeVersion != envvars["DKN_COMPUTE_VERSION"] {
 			fmt.Printf("New dkn-compute version detected (%s), downloading it...\n", computeVersion)
-			if err := utils.DownloadLatestComputeBinary(computeVersion, working_dir, dkn_compute_binary, true); err != nil {
+			if err := utils.DownloadLatestComputeBinary(computeVersion, working_dir, dkn_compute_binary, !*insecure_skip_verify); err != nil {
 				fmt.Printf("Error during downloading the latest dkn-compute binary %s\n", err)
 				utils.ExitWithDelay(1)
 			}
@@ -267,7 +311,7 @@ func main() {
 	} else {
 		// couldn't find the dkn-compute binary, download it
 		fmt.Printf("Downloading the latest dkn-compute binary (%s)\n", computeVersion)
-		if err := utils.DownloadLatestComputeBinary(computeVersion, working_dir, dkn_compute_binary, true); err != nil {
+		if err := utils.DownloadLatestComputeBinary(computeVersion, working_dir, dkn_compute_binary, !*insecure_skip_verify); err != nil {
 			fmt.Printf("Error during downloading the latest dkn-compute binary %s\n", err)
 			utils.ExitWithDelay(1)
 		}
@@ -298,6 +342,51 @@ func main() {
 		exec_command = fmt.Sprintf("./%s", dkn_compute_binary)
 	}
 
+	// If a task group config is given, run every task in parallel instead of
+	// the single-node flow below; all tasks share the version-check/upgrade
+	// cycle and are restarted together whenever a new version is detected.
+	if *group != "" {
+		groupCfg, err := utils.LoadTaskGroupConfig(*group)
+		if err != nil {
+			fmt.Println(err)
+			utils.ExitWithDelay(1)
+		}
+
+		envFor := func(task utils.TaskConfig) []string {
+			taskEnv := make(map[string]string, len(envvars))
+			for k, v := range envvars {
+				taskEnv[k] = v
+			}
+			taskEnv["DKN_MODELS"] = task.Models
+			if task.LogLevel != "" {
+				taskEnv["RUST_LOG"] = task.LogLevel
+			}
+			if task.OpenAIAPIKey != "" {
+				taskEnv["OPENAI_API_KEY"] = task.OpenAIAPIKey
+			}
+			if task.GeminiAPIKey != "" {
+				taskEnv["GEMINI_API_KEY"] = task.GeminiAPIKey
+			}
+			if task.OpenRouterAPIKey != "" {
+				taskEnv["OPENROUTER_API_KEY"] = task.OpenRouterAPIKey
+			}
+			if task.OllamaHost != "" {
+				taskEnv["OLLAMA_HOST"] = task.OllamaHost
+			}
+			if task.OllamaPort != "" {
+				taskEnv["OLLAMA_PORT"] = task.OllamaPort
+			}
+			return utils.MapToList(taskEnv)
+		}
+
+		fmt.Printf("\nStarting task group from %s (%d tasks)...\n\n", *group, len(groupCfg.Tasks))
+		if err := utils.RunTaskGroup(working_dir, exec_command, dkn_compute_binary, envvars["DKN_COMPUTE_VERSION"], groupCfg, envFor, !*insecure_skip_verify); err != nil {
+			fmt.Printf("Task group stopped: %s\n", err)
+			utils.ExitWithDelay(1)
+		}
+		return
+	}
+
 	// Run dkn-compute
 	if *background {
 		fmt.Printf("\nStarting in BACKGROUND mode...\n\n")
@@ -322,26 +411,15 @@ func main() {
 		// FOREGROUND MODE PROCESS:
 		// 1. Starts the compute node binary.
 		// 2. Periodically checks for a new version:
-		//    a. If a new version is detected, downloads it with a temporary name, stops the running process, renames the new file, and restarts.
-		//    b. If no new version is found, sleeps for an hour
+		//    a. If a new version is detected, downloads it under a temporary name and starts it
+		//       alongside the still-running old node (blue/green handover).
+		//    b. Once the new node reports healthy, the old one is stopped and the binary is swapped in place.
+		//    c. If no new version is found, sleeps for an hour.
 		// 3. Manages a single monitoring goroutine to check the compute node's status:
 		//    a. Starts a new goroutine to monitor if the compute node is running, exiting the launcher if it ends or crashes.
-		//    b. Cancels the previous monitoring goroutine if there is an update triggered
-		var monitoringCancel context.CancelFunc
-		for {
-			// Start the compute node
-			pid, err := utils.RunCommand(working_dir, "stdout", false, 0, utils.MapToList(envvars), exec_command)
-			if err != nil {
-				fmt.Printf("ERROR during running exe, %s\n", err)
-				utils.ExitWithDelay(1)
-			}
-			logger.Printf("Compute node started with pid: %d", pid)
-
-			// Create a new context for the current monitoring goroutine
-			var ctx context.Context
-			ctx, monitoringCancel = context.WithCancel(context.Background())
-
-			// Start a goroutine to monitor the node's running status; if it ends or crashes, exit the launcher
+		//    b. Restarted against the new PID after every successful handover.
+		startMonitor := func(pid int) context.CancelFunc {
+			ctx, cancel := context.WithCancel(context.Background())
 			go func(ctx context.Context) {
 				for {
 					// sleep before checking the status
@@ -358,58 +436,412 @@ func main() {
 					}
 				}
 			}(ctx)
+			return cancel
+		}
 
-			// new version check loop
-			for {
-				time.Sleep(60 * time.Minute)
-
-				// Check if a new version is available
-				newVersionAvailable, newVersion := utils.IsNewVersionAvaliable(envvars["DKN_COMPUTE_VERSION"])
-				if newVersionAvailable {
-					logger.Printf("A new compute-node version detected, downloading the new version...")
-					newBinaryTempName := fmt.Sprintf("temp-%s", dkn_compute_binary)
-					if err := utils.DownloadLatestComputeBinary(newVersion, working_dir, newBinaryTempName, false); err != nil {
-						logger.Printf("Error during downloading the latest dkn-compute binary %s\nWill continue to run current one and check again in an hour", err)
-					} else {
-						// successfully downloaded the new binary, now terminating the running one
-						logger.Printf("Successfully downloaded the new version, now terminating the old node...")
-
-						// Cancel the previous monitoring goroutine if it exists
-						if monitoringCancel != nil {
-							monitoringCancel()
-						}
-
-						if err := utils.StopProcess(pid); err != nil {
-							logger.Printf("Error stopping the already running node; %s\n", err)
-							utils.ExitWithDelay(1)
-						}
+		// Start the compute node
+		pid, err := utils.RunCommand(working_dir, "stdout", false, 0, utils.MapToList(envvars), exec_command)
+		if err != nil {
+			fmt.Printf("ERROR during running exe, %s\n", err)
+			utils.ExitWithDelay(1)
+		}
+		logger.Printf("Compute node started with pid: %d", pid)
+		monitoringCancel := startMonitor(pid)
+
+		// stateMu guards pid, envvars, and monitoringCancel: the --watch
+		// hot-reload callback below runs on fsnotify's own goroutine, while
+		// the new-version-check loop further down mutates the same three
+		// variables from this goroutine, so both sides must hold stateMu
+		// whenever they read or write any of them.
+		var stateMu sync.Mutex
+
+		// --watch: fsnotify-watch .env and models.toml and hot-reload the node
+		// whenever the model mix or API keys change, instead of requiring the
+		// operator to kill and re-run the launcher by hand.
+		if *watch {
+			watchPaths := []string{filepath.Join(working_dir, ".env"), filepath.Join(working_dir, "models.toml")}
+			watcher, err := utils.WatchConfigFiles(watchPaths, func(path string) {
+				logger.Printf("Detected change in %s, validating and hot-reloading...", path)
+
+				newEnvvars, err := utils.LoadEnv(working_dir)
+				if err != nil {
+					logger.Printf("Failed to reload env after config change: %s", err)
+					return
+				}
+				// carry over settings that aren't sourced from the watched files
+				stateMu.Lock()
+				newEnvvars["DKN_COMPUTE_VERSION"] = envvars["DKN_COMPUTE_VERSION"]
+				newEnvvars["RUST_LOG"] = envvars["RUST_LOG"]
+				stateMu.Unlock()
+
+				if utils.IsOpenAIRequired(newEnvvars["DKN_MODELS"], &OPENAI_MODELS) && newEnvvars["OPENAI_API_KEY"] == "" {
+					if *non_interactive {
+						logger.Printf("New models require OPENAI_API_KEY but none is set and --non-interactive is given, skipping reload")
+						return
+					}
+					newEnvvars["OPENAI_API_KEY"] = utils.GetUserInput("Enter your OpenAI API Key", true)
+				}
+				if utils.IsGeminiRequired(newEnvvars["DKN_MODELS"], &GEMINI_MODELS) && newEnvvars["GEMINI_API_KEY"] == "" {
+					if *non_interactive {
+						logger.Printf("New models require GEMINI_API_KEY but none is set and --non-interactive is given, skipping reload")
+						return
+					}
+					newEnvvars["GEMINI_API_KEY"] = utils.GetUserInput("Enter your Gemini API Key", true)
+				}
+				if utils.IsOpenRouterRequired(newEnvvars["DKN_MODELS"], &OPENROUTER_MODELS) && newEnvvars["OPENROUTER_API_KEY"] == "" {
+					if *non_interactive {
+						logger.Printf("New models require OPENROUTER_API_KEY but none is set and --non-interactive is given, skipping reload")
+						return
+					}
+					newEnvvars["OPENROUTER_API_KEY"] = utils.GetUserInput("Enter your OpenRoute API Key", true)
+				}
+				if utils.IsOllamaRequired(newEnvvars["DKN_MODELS"], &OLLAMA_MODELS) {
+					ollamaHost, ollamaPort := utils.HandleOllamaEnv(newEnvvars["OLLAMA_HOST"], newEnvvars["OLLAMA_PORT"])
+					newEnvvars["OLLAMA_HOST"] = ollamaHost
+					newEnvvars["OLLAMA_PORT"] = ollamaPort
+				}
 
-						// delete the old binary
-						logger.Printf("Node successfully terminated by the launcher, changing the new version binary with the old one...")
-						if err := utils.DeleteFile(working_dir, dkn_compute_binary); err != nil {
-							logger.Printf("Error during deleting the old binary file; %s\n", err)
-							utils.ExitWithDelay(1)
-						}
+				logger.Printf("Configuration validated, restarting the node with the new configuration...")
+				stateMu.Lock()
+				defer stateMu.Unlock()
+				monitoringCancel()
+				if err := utils.StopProcess(pid); err != nil {
+					logger.Printf("Error stopping the running node for hot-reload; %s", err)
+					return
+				}
+				newPid, err := utils.RunCommand(working_dir, "stdout", false, 0, utils.MapToList(newEnvvars), exec_command)
+				if err != nil {
+					logger.Printf("Error restarting the node after hot-reload; %s", err)
+					return
+				}
+				pid = newPid
+				envvars = newEnvvars
+				monitoringCancel = startMonitor(pid)
+				logger.Printf("Node restarted with pid: %d using the updated configuration", pid)
+			})
+			if err != nil {
+				logger.Printf("Failed to start config watcher: %s", err)
+			} else {
+				defer watcher.Close()
+			}
+		}
 
-						// rename the new downloaded file
-						if err := utils.RenameFile(working_dir, newBinaryTempName, dkn_compute_binary); err != nil {
-							logger.Printf("Error during renaming the new version binary; %s\n", err)
-							utils.ExitWithDelay(1)
-						}
-						// new binaries are ready, now break this loop to restart with the new binaries
-						envvars["DKN_COMPUTE_VERSION"] = newVersion
-						if err := utils.DumpEnvVarsToFile(&envvars, filepath.Join(working_dir, ".env")); err != nil {
-							fmt.Printf("Failed to dump the .env file, continuing to running the node though. error message: %s\n", err)
-						}
-						logger.Printf("All good, now restarting the node with new version...")
-						break
-					}
-				} else if *dev {
+		// new version check loop
+		for {
+			time.Sleep(60 * time.Minute)
+
+			// snapshot the shared state once per iteration: everything below
+			// until the handover only reads it, and the --watch hot-reload
+			// callback may reassign envvars concurrently
+			stateMu.Lock()
+			currentComputeVersion := envvars["DKN_COMPUTE_VERSION"]
+			envvarsSnapshot := utils.MapToList(envvars)
+			healthPortRaw := envvars["DKN_COMPUTE_HEALTH_PORT"]
+			stateMu.Unlock()
+
+			// Check if a new version is available
+			newVersionAvailable, newVersion := utils.IsNewVersionAvaliable(currentComputeVersion)
+			if !newVersionAvailable {
+				if *dev {
 					// no new version detected, will check it again after a bit
 					// only log this for dev (debug) mode
 					logger.Printf("No new compute-node version detected, will check again in an hour.")
 				}
+				continue
+			}
+
+			logger.Printf("A new compute-node version detected, downloading the new version...")
+			newBinaryTempName := fmt.Sprintf("temp-%s", dkn_compute_binary)
+			if err := utils.DownloadLatestComputeBinary(newVersion, working_dir, newBinaryTempName, !*insecure_skip_verify); err != nil {
+				logger.Printf("Error during downloading the latest dkn-compute binary %s\nWill continue to run current one and check again in an hour", err)
+				continue
+			}
+
+			// start the new binary alongside the still-running old one, under its temporary name
+			newExecCommand := ""
+			if runtime.GOOS == "windows" {
+				newExecCommand = fmt.Sprintf(".\\%s", newBinaryTempName)
+			} else {
+				newExecCommand = fmt.Sprintf("./%s", newBinaryTempName)
+			}
+			logger.Printf("Starting the new version alongside the running node for a zero-downtime handover...")
+			newPid, err := utils.RunCommand(working_dir, fmt.Sprintf("file:logs-%s.txt", newVersion), false, 0, envvarsSnapshot, newExecCommand)
+			if err != nil {
+				logger.Printf("Error starting the new version; %s\nWill continue to run current one and check again in an hour", err)
+				continue
+			}
+
+			// health probe: a TCP port if DKN_COMPUTE_HEALTH_PORT is set, otherwise a readiness file the binary is expected to touch
+			healthPort := 0
+			if healthPortRaw != "" {
+				if parsed, err := strconv.Atoi(healthPortRaw); err == nil {
+					healthPort = parsed
+				}
+			}
+			readinessPath := filepath.Join(working_dir, fmt.Sprintf(".%s-ready", newBinaryTempName))
+			if err := utils.WaitForHealthy(newPid, healthPort, readinessPath, 30*time.Second); err != nil {
+				logger.Printf("New version failed to become healthy (%s), killing it and keeping the old one running", err)
+				utils.StopProcess(newPid)
+				utils.DeleteFile(working_dir, newBinaryTempName)
+				continue
+			}
+
+			logger.Printf("New version is healthy, terminating the old node...")
+			stateMu.Lock()
+			monitoringCancel()
+			if err := utils.StopProcess(pid); err != nil {
+				logger.Printf("Error stopping the already running node; %s\n", err)
+				utils.ExitWithDelay(1)
+			}
+
+			logger.Printf("Old node terminated, swapping the binary in place...")
+			if err := utils.DeleteFile(working_dir, dkn_compute_binary); err != nil {
+				logger.Printf("Error during deleting the old binary file; %s\n", err)
+				utils.ExitWithDelay(1)
+			}
+			if err := utils.RenameFile(working_dir, newBinaryTempName, dkn_compute_binary); err != nil {
+				logger.Printf("Error during renaming the new version binary; %s\n", err)
+				utils.ExitWithDelay(1)
+			}
+
+			pid = newPid
+			envvars["DKN_COMPUTE_VERSION"] = newVersion
+			if err := utils.DumpEnvVarsToFile(&envvars, filepath.Join(working_dir, ".env")); err != nil {
+				fmt.Printf("Failed to dump the .env file, continuing to running the node though. error message: %s\n", err)
+			}
+			monitoringCancel = startMonitor(pid)
+			stateMu.Unlock()
+			logger.Printf("All good, now running on the new version with zero downtime...")
+		}
+	}
+}
+
+// runDockerCommand implements the `--docker` deployment mode: it generates
+// a docker-compose.yml for the resolved compute version, brings it up, and
+// then periodically checks for new dkn-compute versions, translating the
+// native upgrade loop into docker pull + up -d + down.
+//
+// Parameters:
+//   - workingDir: directory the docker-compose.yml is generated into.
+//   - computeVersion: the resolved dkn-compute version to pin the image to.
+//   - envvars: the finalized env vars, passed through to the dkn-compute container.
+//   - platform: optional image platform override (e.g. linux/arm64).
+//   - registry: optional image registry override.
+//   - forcePull: whether to pull images before the initial `up -d`.
+func runDockerCommand(workingDir, computeVersion string, envvars map[string]string, platform, registry string, forcePull bool) {
+	if !utils.IsDockerUp(10 * time.Second) {
+		utils.ExitWithDelay(1)
+	}
+	// exits with a delay itself if neither `docker compose` nor `docker-compose` is available
+	utils.CheckDockerComposeCommand()
+
+	useOllama := envvars["OLLAMA_HOST"] != "" || utils.IsOllamaRequired(envvars["DKN_MODELS"], &OLLAMA_MODELS)
+	opts := utils.DockerComposeOptions{
+		ComputeVersion: computeVersion,
+		Registry:       registry,
+		Platform:       platform,
+		Envvars:        envvars,
+		UseOllama:      useOllama,
+		OllamaPort:     envvars["OLLAMA_PORT"],
+	}
+	if err := utils.GenerateDockerComposeFile(workingDir, opts); err != nil {
+		fmt.Printf("Failed to generate %s: %s\n", utils.DockerComposeFileName, err)
+		utils.ExitWithDelay(1)
+	}
+
+	if forcePull {
+		if err := utils.DockerComposePull(workingDir); err != nil {
+			fmt.Printf("Failed to pull docker images: %s\n", err)
+			utils.ExitWithDelay(1)
+		}
+	}
+
+	if err := utils.RunDockerComposeUp(workingDir); err != nil {
+		fmt.Printf("Failed to bring up docker compose deployment: %s\n", err)
+		utils.ExitWithDelay(1)
+	}
+	fmt.Println("dkn-compute is up via docker compose. Checking for updates every hour...")
+
+	for {
+		time.Sleep(60 * time.Minute)
+
+		if !utils.IsContainerHealthy("dkn-compute") {
+			fmt.Println("dkn-compute container is unhealthy, exiting the launcher")
+			utils.ExitWithDelay(1)
+		}
+
+		newVersionAvailable, newVersion := utils.IsNewVersionAvaliable(computeVersion)
+		if !newVersionAvailable {
+			continue
+		}
+
+		fmt.Printf("A new compute-node version detected (%s), updating the docker deployment...\n", newVersion)
+		computeVersion = newVersion
+		opts.ComputeVersion = computeVersion
+		if err := utils.GenerateDockerComposeFile(workingDir, opts); err != nil {
+			fmt.Printf("Failed to regenerate %s: %s\n", utils.DockerComposeFileName, err)
+			continue
+		}
+		if err := utils.DockerComposePull(workingDir); err != nil {
+			fmt.Printf("Failed to pull new docker images: %s\n", err)
+			continue
+		}
+		if err := utils.RunDockerComposeDown(workingDir); err != nil {
+			fmt.Printf("Failed to bring down the old docker deployment: %s\n", err)
+			continue
+		}
+		if err := utils.RunDockerComposeUp(workingDir); err != nil {
+			fmt.Printf("Failed to bring up the new docker deployment: %s\n", err)
+			continue
+		}
+		fmt.Printf("Docker deployment updated to %s\n", computeVersion)
+	}
+}
+
+// pickedModelsOf filters a comma-separated "picked models" string down to
+// just the entries that also appear in candidates, preserving order.
+func pickedModelsOf(pickedModels string, candidates []string) []string {
+	candidateSet := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		candidateSet[c] = true
+	}
+
+	var picked []string
+	for _, model := range strings.Split(pickedModels, ",") {
+		if candidateSet[model] {
+			picked = append(picked, model)
+		}
+	}
+	return picked
+}
+
+// runDaemonCommand implements the `launcher daemon` subcommand: it starts
+// the supervisor and serves the local control API until the process is
+// killed, letting callers submit/list/stop dkn-compute jobs over IPC
+// instead of running a single node in the foreground.
+//
+// Parameters:
+//   - args: the subcommand's own argument list (os.Args[2:]).
+func runDaemonCommand(args []string) {
+	daemonFlags := flag.NewFlagSet("daemon", flag.ExitOnError)
+	socketFlag := daemonFlags.String("socket", "", "Path to the IPC socket/named pipe (default: <working-dir>/dkn-launcher.sock)")
+	daemonFlags.Parse(args)
+
+	working_dir := utils.GetWorkingDir()
+	dkn_compute_binary := utils.ComputeBinaryFileName()
+
+	exec_command := ""
+	if runtime.GOOS == "windows" {
+		exec_command = fmt.Sprintf(".\\%s", dkn_compute_binary)
+	} else {
+		exec_command = fmt.Sprintf("./%s", dkn_compute_binary)
+	}
+
+	socketPath := *socketFlag
+	if socketPath == "" {
+		socketPath = utils.DefaultSocketPath(working_dir)
+	}
+
+	supervisor := utils.NewSupervisor(working_dir)
+
+	// on shutdown, stop every job the supervisor is tracking instead of
+	// leaving them running once the daemon process itself is gone
+	utils.DefaultDeathWatcher.OnShutdown(func(ctx context.Context) error {
+		var failed []string
+		for _, job := range supervisor.ListJobs() {
+			if err := supervisor.StopJob(job.ID); err != nil {
+				failed = append(failed, job.ID)
 			}
 		}
+		if len(failed) > 0 {
+			return fmt.Errorf("failed to stop job(s): %s", strings.Join(failed, ", "))
+		}
+		return nil
+	})
+	go utils.DefaultDeathWatcher.Watch()
+
+	fmt.Printf("Launcher daemon listening on %s\n", socketPath)
+	if err := utils.ServeDaemon(socketPath, supervisor, exec_command); err != nil {
+		fmt.Printf("Daemon stopped: %s\n", err)
+		utils.ExitWithDelay(1)
+	}
+}
+
+// runDaemonClientCommand implements the `launcher submit|list|status|stream|stop`
+// subcommands: each dials an already-running `launcher daemon` over its IPC
+// socket, sends a single DaemonRequest for action, and prints the result.
+//
+// Parameters:
+//   - action: one of "submit", "list", "status", "stream", "stop".
+//   - args: the subcommand's own argument list (os.Args[2:]).
+func runDaemonClientCommand(action string, args []string) {
+	clientFlags := flag.NewFlagSet(action, flag.ExitOnError)
+	socketFlag := clientFlags.String("socket", "", "Path to the IPC socket/named pipe (default: <working-dir>/dkn-launcher.sock)")
+	clientFlags.Parse(args)
+
+	working_dir := utils.GetWorkingDir()
+	socketPath := *socketFlag
+	if socketPath == "" {
+		socketPath = utils.DefaultSocketPath(working_dir)
+	}
+
+	jobID := ""
+	if action != "list" {
+		if clientFlags.NArg() < 1 {
+			fmt.Printf("Usage: launcher %s [--socket <path>] <job-id>\n", action)
+			utils.ExitWithDelay(1)
+		}
+		jobID = clientFlags.Arg(0)
+	}
+
+	conn, err := utils.DialDaemon(socketPath)
+	if err != nil {
+		fmt.Println(err)
+		utils.ExitWithDelay(1)
+	}
+	defer conn.Close()
+
+	req := utils.DaemonRequest{Action: action, JobID: jobID}
+
+	if action == "submit" {
+		envvars, err := utils.LoadEnv(working_dir)
+		if err != nil {
+			fmt.Println(err)
+			utils.ExitWithDelay(1)
+		}
+		req.Env = envvars
+	}
+
+	if action == "stream" {
+		err := utils.StreamDaemonRequest(conn, req, func(line string) error {
+			fmt.Println(line)
+			return nil
+		})
+		if err != nil {
+			fmt.Println(err)
+			utils.ExitWithDelay(1)
+		}
+		return
+	}
+
+	resp, err := utils.SendDaemonRequest(conn, req)
+	if err != nil {
+		fmt.Println(err)
+		utils.ExitWithDelay(1)
+	}
+	if !resp.OK {
+		fmt.Println(resp.Error)
+		utils.ExitWithDelay(1)
+	}
+
+	switch action {
+	case "list", "status":
+		for _, job := range resp.Jobs {
+			fmt.Printf("%s\tpid=%d\tstate=%s\tcreated=%s\n", job.ID, job.PID, job.State, job.CreatedAt.Format("2006-01-02 15:04:05"))
+		}
+	case "submit":
+		fmt.Printf("Job %q submitted with pid %d\n", jobID, resp.Jobs[0].PID)
+	case "stop":
+		fmt.Printf("Job %q stopped\n", jobID)
 	}
 }
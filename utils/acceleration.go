@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Acceleration kinds returned by DetectAcceleration.
+const (
+	AccelerationNvidia = "nvidia"
+	AccelerationROCm   = "rocm"
+	AccelerationMetal  = "metal"
+	AccelerationCPU    = "cpu"
+)
+
+// DetectAcceleration inspects the host for GPU acceleration support, in
+// order of preference: NVIDIA (via `nvidia-smi`), AMD ROCm (via `rocm-smi`
+// or `/dev/kfd`), then Apple Metal (darwin+arm64). Falls back to
+// AccelerationCPU when none of these are found.
+//
+// Parameters:
+//   - (none)
+//
+// Returns:
+//   - string: one of AccelerationNvidia, AccelerationROCm, AccelerationMetal, AccelerationCPU.
+//   - map[string]string: free-form details about the detected device, for logging.
+func DetectAcceleration() (string, map[string]string) {
+	if details, ok := detectNvidia(); ok {
+		return AccelerationNvidia, details
+	}
+	if details, ok := detectROCm(); ok {
+		return AccelerationROCm, details
+	}
+	if runtime.GOOS == "darwin" && runtime.GOARCH == "arm64" {
+		return AccelerationMetal, map[string]string{"chip": "apple-silicon"}
+	}
+	return AccelerationCPU, nil
+}
+
+// detectNvidia shells out to `nvidia-smi` to check for an NVIDIA GPU and
+// read back its name and driver version.
+func detectNvidia() (map[string]string, bool) {
+	if !IsCommandAvailable("nvidia-smi") {
+		return nil, false
+	}
+
+	out, err := exec.Command("nvidia-smi", "--query-gpu=name,driver_version", "--format=csv,noheader").Output()
+	if err != nil || len(strings.TrimSpace(string(out))) == 0 {
+		return nil, false
+	}
+
+	fields := strings.SplitN(strings.TrimSpace(strings.Split(string(out), "\n")[0]), ",", 2)
+	details := map[string]string{"gpu": strings.TrimSpace(fields[0])}
+	if len(fields) > 1 {
+		details["driver_version"] = strings.TrimSpace(fields[1])
+	}
+	return details, true
+}
+
+// AccelerationEnvVars returns the extra environment variables `ollama serve`
+// should be launched with for the given acceleration kind, so the server
+// actually uses the detected GPU instead of defaulting to CPU.
+func AccelerationEnvVars(kind string) []string {
+	switch kind {
+	case AccelerationNvidia:
+		return []string{"OLLAMA_NUM_GPU=999", "OLLAMA_KEEP_ALIVE=5m"}
+	case AccelerationROCm:
+		return []string{"OLLAMA_NUM_GPU=999", "HSA_OVERRIDE_GFX_VERSION=10.3.0", "OLLAMA_KEEP_ALIVE=5m"}
+	case AccelerationMetal:
+		return []string{"OLLAMA_KEEP_ALIVE=5m"}
+	default:
+		return nil
+	}
+}
+
+// detectROCm checks for an AMD ROCm-capable GPU, either via the `rocm-smi`
+// CLI or the presence of the `/dev/kfd` kernel driver device.
+func detectROCm() (map[string]string, bool) {
+	if IsCommandAvailable("rocm-smi") {
+		out, err := exec.Command("rocm-smi", "--showproductname").Output()
+		if err == nil && len(strings.TrimSpace(string(out))) > 0 {
+			return map[string]string{"gpu": strings.TrimSpace(string(out))}, true
+		}
+	}
+
+	if _, err := os.Stat("/dev/kfd"); err == nil {
+		return map[string]string{"gpu": "amd (via /dev/kfd)"}, true
+	}
+
+	return nil, false
+}
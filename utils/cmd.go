@@ -3,15 +3,12 @@ package utils
 import (
 	"bufio"
 	"context"
-	"encoding/hex"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
-	"syscall"
 	"time"
 )
 
@@ -29,6 +26,7 @@ func IsCommandAvailable(command string) bool {
 
 // RunCommand executes a command in a specified working directory, with options to print output
 // to stdout, wait for completion, set custom environment variables, and optionally specify a timeout.
+// It's a thin wrapper around RunCommandWithOptions for callers that don't need a pluggable log sink.
 //
 // Parameters:
 //   - working_dir: The directory where the command will be executed.
@@ -43,20 +41,30 @@ func IsCommandAvailable(command string) bool {
 //   - int: The PID of the started command.
 //   - error: Returns an error if the command fails to start, times out, or completes with an error.
 func RunCommand(working_dir string, outputDest string, wait bool, timeout time.Duration, envs []string, command string, args ...string) (int, error) {
-	var cmd *exec.Cmd
-	var ctx context.Context
-	var cancel context.CancelFunc
+	return RunCommandWithOptions(working_dir, outputDest, wait, timeout, envs, nil, command, args...)
+}
 
-	// Create the command with or without a timeout depending on the timeout value
+// RunCommandWithOptions is RunCommand plus a LogOptions for the "sink" outputDest, which
+// streams the command's stdout/stderr through a pluggable LogSink (rotating file, JSON
+// lines, syslog, or a remote log endpoint) instead of the plain "file"/"stdout"/"none"
+// destinations. opts is ignored for every other outputDest and may be nil.
+//
+// Internally this is a thin wrapper over DefaultCommandRunner: it starts the
+// command via CommandRunner.Start and pumps the returned Handle's line-oriented
+// Stdout/Stderr channels into whichever destination was requested.
+//
+// Returns:
+//   - int: The PID of the started command.
+//   - error: Returns an error if the command fails to start, times out, or completes with an error.
+func RunCommandWithOptions(working_dir string, outputDest string, wait bool, timeout time.Duration, envs []string, opts *LogOptions, command string, args ...string) (int, error) {
+	var cmd *exec.Cmd
+	ctx := context.Background()
 	if timeout > 0 {
-		// Create a context with timeout
-		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
-		cmd = exec.CommandContext(ctx, command, args...)
-	} else {
-		// No timeout, use regular command
-		cmd = exec.Command(command, args...)
 	}
+	cmd = exec.CommandContext(ctx, command, args...)
 
 	// Set environment variables
 	cmd.Env = append(os.Environ(), envs...)
@@ -64,68 +72,118 @@ func RunCommand(working_dir string, outputDest string, wait bool, timeout time.D
 	// Set working dir
 	cmd.Dir = working_dir
 
+	// Run the command in its own process group so StopProcessCtx can signal
+	// the whole group (and any child workers it spawns) instead of just this one PID.
+	setProcessGroup(cmd)
+
+	// writeLine delivers one line of output to outputDest. "file" logs to
+	// logs.txt, while "file:<name>" logs to a caller-chosen file (e.g.
+	// "logs-<taskname>.txt" so parallel task-group members don't clobber each
+	// other's logs). "sink" streams through a pluggable LogSink instead of a
+	// single truncated file, so long-running nodes can rotate logs or ship
+	// them off-box.
 	var logFile *os.File
-	var err error
-
-	// Set output handling based on outputDest
-	switch outputDest {
-	case "stdout":
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-	case "file":
-		logFile, err = os.OpenFile(filepath.Join(working_dir, "logs.txt"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	var logSink LogSink
+	var writeLine func(line string, stderr bool)
+
+	switch {
+	case outputDest == "stdout":
+		writeLine = func(line string, stderr bool) {
+			if stderr {
+				fmt.Fprintln(os.Stderr, line)
+			} else {
+				fmt.Fprintln(os.Stdout, line)
+			}
+		}
+	case outputDest == "file" || strings.HasPrefix(outputDest, "file:"):
+		logName := "logs.txt"
+		if rest := strings.TrimPrefix(outputDest, "file:"); rest != outputDest {
+			logName = rest
+		}
+		var err error
+		logFile, err = os.OpenFile(filepath.Join(working_dir, logName), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 		if err != nil {
 			return 0, fmt.Errorf("failed to open log file: %w", err)
 		}
 		// Close the log file when the function ends
 		defer logFile.Close()
-		cmd.Stdout = logFile
-		cmd.Stderr = logFile
-	case "none":
-		cmd.Stdout = nil
-		cmd.Stderr = nil
+		writeLine = func(line string, _ bool) {
+			fmt.Fprintln(logFile, line)
+		}
+	case outputDest == "sink":
+		sink, err := buildLogSink(working_dir, opts)
+		if err != nil {
+			return 0, fmt.Errorf("failed to set up log sink: %w", err)
+		}
+		logSink = sink
+		writeLine = func(line string, stderr bool) {
+			logSink.WriteLine(line, stderr)
+		}
+	case outputDest == "none":
+		writeLine = func(line string, stderr bool) {}
 	default:
 		return 0, fmt.Errorf("invalid output destination: %s", outputDest)
 	}
 
-	// Start the command
-	err = cmd.Start()
+	handle, err := DefaultCommandRunner.Start(ctx, cmd)
 	if err != nil {
 		return 0, fmt.Errorf("failed to start command: %w", err)
 	}
+	// RunCommand callers don't feed the process stdin; close it immediately
+	// so the command sees EOF instead of blocking on a read, matching the
+	// pre-refactor behavior of an unset (/dev/null) cmd.Stdin.
+	handle.Stdin().Close()
 
-	// Get the PID
 	pid := cmd.Process.Pid
+	// Track pid so DeathWatcher can stop it on a shutdown signal even if the
+	// caller never waits on it again (the common case for wait=false callers).
+	DefaultDeathWatcher.Track(pid)
+
+	pump := func() {
+		stdout, stderr := handle.Stdout(), handle.Stderr()
+		for stdout != nil || stderr != nil {
+			select {
+			case line, ok := <-stdout:
+				if !ok {
+					stdout = nil
+					continue
+				}
+				writeLine(line, false)
+			case line, ok := <-stderr:
+				if !ok {
+					stderr = nil
+					continue
+				}
+				writeLine(line, true)
+			}
+		}
+	}
 
 	// If wait is false, handle output asynchronously
 	if !wait {
 		go func() {
-			// Ensure to check if logFile is not nil
-			if logFile != nil {
-				// Start goroutines to copy the command's stdout and stderr to the log file
-				stdoutPipe, stdoutErr := cmd.StdoutPipe()
-				stderrPipe, stderrErr := cmd.StderrPipe()
-
-				// Check for pipe errors before starting goroutines
-				if stdoutErr == nil && stdoutPipe != nil {
-					go io.Copy(logFile, stdoutPipe)
-				}
-				if stderrErr == nil && stderrPipe != nil {
-					go io.Copy(logFile, stderrPipe)
-				}
+			pump()
+			handle.Wait()
+			DefaultDeathWatcher.Untrack(pid)
+			if logSink != nil {
+				logSink.Close()
 			}
-			// Ensure the process runs to completion
-			cmd.Wait()
 		}()
-	} else {
-		// If wait is true, wait for the command to finish
-		err = cmd.Wait()
-		if timeout > 0 && ctx.Err() == context.DeadlineExceeded {
-			return pid, ctx.Err()
-		}
-		if err != nil {
-			return pid, fmt.Errorf("command finished with error; %w", err)
-		}
+		return pid, nil
+	}
+
+	// If wait is true, wait for the command to finish
+	pump()
+	_, waitErr := handle.Wait()
+	DefaultDeathWatcher.Untrack(pid)
+	if logSink != nil {
+		logSink.Close()
+	}
+	if timeout > 0 && ctx.Err() == context.DeadlineExceeded {
+		return pid, ctx.Err()
+	}
+	if waitErr != nil {
+		return pid, fmt.Errorf("command finished with error; %w", waitErr)
 	}
 
 	return pid, nil
@@ -136,11 +194,64 @@ func RunCommand(working_dir string, outputDest string, wait bool, timeout time.D
 // Parameters:
 //   - openai_models: A slice of available OpenAI model names.
 //   - gemini_models: A slice of available Gemini model names.
-//   - ollama_models: A slice of available Ollama model names.
+//   - openrouter_models: A slice of available OpenRouter model names.
+//   - ollama_models: A slice of available Ollama model names from the catalog.
+//
+// Returns:
+//   - string: A comma-separated string of selected model names.
+func PickModels(openai_models, gemini_models, openrouter_models, ollama_models []string) string {
+	return PickModelsFiltered(openai_models, gemini_models, openrouter_models, ollama_models, OllamaModelFilter{})
+}
+
+// modelRow is one selectable entry in PickModelsFiltered' table: a provider
+// label plus a model name, at the (1-based) position the user types to pick it.
+type modelRow struct {
+	provider string
+	name     string
+}
+
+// PickModelsFiltered is PickModels plus an OllamaModelFilter, applied only to
+// the Ollama models auto-discovered locally via DiscoverOllamaModels (the
+// catalog-provided ollama_models are always offered as-is, since we have no
+// metadata to filter them on). The table is paged to the terminal height so
+// large catalogs don't scroll past the screen.
+//
+// Parameters:
+//   - openai_models: A slice of available OpenAI model names.
+//   - gemini_models: A slice of available Gemini model names.
+//   - openrouter_models: A slice of available OpenRouter model names.
+//   - ollama_models: A slice of available Ollama model names from the catalog.
+//   - filter: Narrows which locally auto-discovered Ollama models are offered.
 //
 // Returns:
 //   - string: A comma-separated string of selected model names.
-func PickModels(openai_models, gemini_models, ollama_models []string) string {
+func PickModelsFiltered(openai_models, gemini_models, openrouter_models, ollama_models []string, filter OllamaModelFilter) string {
+	localOllama, err := DiscoverOllamaModels()
+	if err != nil {
+		fmt.Printf("Warning: could not auto-discover local Ollama models: %s\n", err)
+	}
+	localOllama = FilterOllamaModels(localOllama, filter)
+
+	ollamaNames := append([]string{}, ollama_models...)
+	for _, m := range localOllama {
+		if !containsString(ollamaNames, m.Name) {
+			ollamaNames = append(ollamaNames, m.Name)
+		}
+	}
+
+	var rows []modelRow
+	for _, m := range openai_models {
+		rows = append(rows, modelRow{"OpenAI", m})
+	}
+	for _, m := range gemini_models {
+		rows = append(rows, modelRow{"Gemini", m})
+	}
+	for _, m := range openrouter_models {
+		rows = append(rows, modelRow{"OpenRouter", m})
+	}
+	for _, m := range ollamaNames {
+		rows = append(rows, modelRow{"Ollama", m})
+	}
 
 	// column widths
 	idWidth := 4
@@ -150,31 +261,28 @@ func PickModels(openai_models, gemini_models, ollama_models []string) string {
 	header := fmt.Sprintf("| %-*s | %-*s | %-*s |", idWidth, "ID", providerWidth, "Provider", nameWidth, "Name")
 	separator := "+" + strings.Repeat("-", idWidth+2) + "+" + strings.Repeat("-", providerWidth+2) + "+" + strings.Repeat("-", nameWidth+2) + "+"
 
-	// print the table
 	fmt.Print("\nPlease pick the model you want to run:\n\n")
-	fmt.Println(separator)
-	fmt.Println(header)
-	fmt.Println(separator)
-
-	// print the rows
-	for id, model := range openai_models {
-		modelId := id + 1
-		provider := "OpenAI"
-		fmt.Printf("| %-*d | %-*s | %-*s |\n", idWidth, modelId, providerWidth, provider, nameWidth, model)
-	}
-	for id, model := range gemini_models {
-		modelId := len(openai_models) + id + 1
-		provider := "Gemini"
-		fmt.Printf("| %-*d | %-*s | %-*s |\n", idWidth, modelId, providerWidth, provider, nameWidth, model)
-	}
-	for id, model := range ollama_models {
-		modelId := len(openai_models) + len(gemini_models) + id + 1
-		provider := "Ollama"
-		fmt.Printf("| %-*d | %-*s | %-*s |\n", idWidth, modelId, providerWidth, provider, nameWidth, model)
-	}
 
-	// print end
-	fmt.Println(separator)
+	pageSize := terminalPageSize()
+	pageCount := (len(rows) + pageSize - 1) / pageSize
+	for start := 0; start < len(rows); start += pageSize {
+		end := start + pageSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		fmt.Println(separator)
+		fmt.Println(header)
+		fmt.Println(separator)
+		for i := start; i < end; i++ {
+			fmt.Printf("| %-*d | %-*s | %-*s |\n", idWidth, i+1, providerWidth, rows[i].provider, nameWidth, rows[i].name)
+		}
+		fmt.Println(separator)
+
+		if end < len(rows) {
+			GetUserInput(fmt.Sprintf("-- page %d/%d, press enter to see more --", start/pageSize+1, pageCount), false)
+		}
+	}
 
 	models := GetUserInput("Enter the model ids (comma separated, e.g: 1,2,4) ", true)
 
@@ -195,32 +303,16 @@ func PickModels(openai_models, gemini_models, ollama_models []string) string {
 			invalid_selections[i] = true
 			continue
 		}
-		if id > 0 && id <= len(openai_models) {
-			// openai model picked
-			if !picked_models_map[id] {
-				// if not already picked, add it to bin
-				picked_models_map[id] = true
-				picked_models_str = fmt.Sprintf("%s,%s", picked_models_str, openai_models[id-1])
-			}
-		} else if id > len(openai_models) && id <= len(gemini_models)+len(openai_models) {
-			// gemini model picked
-			if !picked_models_map[id] {
-				// if not already picked, add it to bin
-				picked_models_map[id] = true
-				picked_models_str = fmt.Sprintf("%s,%s", picked_models_str, gemini_models[id-len(openai_models)-1])
-			}
-		} else if id > len(openai_models)+len(gemini_models) && id <= len(ollama_models)+len(gemini_models)+len(openai_models) {
-			// ollama model picked
-			if !picked_models_map[id] {
-				// if not already picked, add it to bin
-				picked_models_map[id] = true
-				picked_models_str = fmt.Sprintf("%s,%s", picked_models_str, ollama_models[id-len(gemini_models)-len(openai_models)-1])
-			}
-		} else {
+		if id < 1 || id > len(rows) {
 			// out of index, invalid
 			invalid_selections[i] = true
 			continue
 		}
+		if !picked_models_map[id] {
+			// if not already picked, add it to bin
+			picked_models_map[id] = true
+			picked_models_str = fmt.Sprintf("%s,%s", picked_models_str, rows[id-1].name)
+		}
 	}
 	if len(invalid_selections) != 0 {
 		fmt.Printf("Skipping the invalid selections: %s \n\n", FormatMapKeys(invalid_selections))
@@ -228,6 +320,25 @@ func PickModels(openai_models, gemini_models, ollama_models []string) string {
 	return picked_models_str
 }
 
+// terminalPageSize returns how many model rows PickModelsFiltered renders per
+// page, leaving room for the header/separators/prompt. It reads $LINES (set
+// by most shells) and falls back to a sane default when unset or unparsable.
+func terminalPageSize() int {
+	const defaultTerminalLines = 24
+	const chromeLines = 6 // header + 3 separators + page prompt + 1 line of margin
+
+	lines := defaultTerminalLines
+	if raw := os.Getenv("LINES"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			lines = n
+		}
+	}
+	if lines <= chromeLines {
+		return 1
+	}
+	return lines - chromeLines
+}
+
 // GetUserInput reads a line of input from the terminal and optionally trims spaces.
 //
 // Parameters:
@@ -262,25 +373,8 @@ func ExitWithDelay(code int) {
 	os.Exit(code)
 }
 
-// GetDknSecretKey prompts the user to enter their DKN Wallet Secret Key, validates it, and returns it.
-//
-// Returns:
-//   - string: The validated DKN Wallet Secret Key.
-//   - error: Returns an error if the key is not 32-bytes hex encoded or if there are decoding issues.
-func GetDknSecretKey() (string, error) {
-	skey := GetUserInput("Please enter your DKN Wallet Secret Key (32-bytes hex encoded) ", true)
-	skey = strings.TrimPrefix(skey, "0x")
-	// decode the hex string into bytes
-	decoded_skey, err := hex.DecodeString(skey)
-	if err != nil {
-		return "", fmt.Errorf("DKN Wallet Secret Key should be 32-bytes hex encoded")
-	}
-	// ensure the decoded bytes are exactly 32 bytes
-	if len(decoded_skey) != 32 {
-		return "", fmt.Errorf("DKN Wallet Secret Key should be 32 bytes long")
-	}
-	return skey, nil
-}
+// GetDknSecretKey, validateSecretKey, and the SecretProvider implementations
+// that wrap them are in secret_provider.go.
 
 // ModelList is a type that allows multiple values for the -m command-line flag.
 type ModelList []string
@@ -306,35 +400,10 @@ func (models *ModelList) Set(value string) error {
 	return nil
 }
 
-// isProcessRunning checks if a process with the given PID is running.
-func IsProcessRunning(pid int) bool {
-	// Try to find the process
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		// If there's an error finding the process, it's not running
-		return false
-	}
-
-	// Try to send signal 0 to the process (this does not kill it)
-	err = process.Signal(syscall.Signal(0))
-	return err == nil
-}
-
-// stopProcess stops a process by its PID.
-func StopProcess(pid int) error {
-	// Find the process by PID
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return fmt.Errorf("could not find process: %w", err)
-	}
-
-	// Send the SIGTERM signal to the process to terminate it gracefully
-	if err := process.Signal(syscall.SIGTERM); err != nil {
-		return fmt.Errorf("could not terminate process: %w", err)
-	}
-
-	return fmt.Errorf("")
-}
+// IsProcessRunning and StopProcess/StopProcessCtx are implemented per-OS in
+// process_unix.go and process_windows.go, since process-group signaling and
+// tree cleanup differ enough between the two that a shared implementation
+// would just be a pile of runtime.GOOS checks.
 
 // renameFile renames a file in the given working directory.
 func RenameFile(workingDir, oldName, newName string) error {
@@ -0,0 +1,200 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RunResult carries the outcome of a CommandRunner.Run call: captured
+// stdout/stderr, the process exit code, how long it took, and the command
+// line it ran, so callers can wrap errors with useful context without
+// having held onto the original *exec.Cmd.
+type RunResult struct {
+	Stdout      bytes.Buffer
+	Stderr      bytes.Buffer
+	ExitCode    int
+	Duration    time.Duration
+	CommandLine string
+}
+
+// Handle is a running command started via CommandRunner.Start. Unlike Run,
+// which blocks until the command finishes, a Handle lets a caller interact
+// with the process while it's alive: answer an interactive prompt, tail its
+// output line by line, or send it a signal.
+type Handle interface {
+	// Stdin is the process's standard input. Callers that don't need to
+	// write to it should close it so the process sees EOF instead of
+	// blocking on a read.
+	Stdin() io.WriteCloser
+	// Stdout streams the process's stdout one line at a time; the channel
+	// closes together with Stderr's, once both stdout and stderr are exhausted.
+	Stdout() <-chan string
+	// Stderr streams the process's stderr one line at a time; the channel
+	// closes together with Stdout's, once both stdout and stderr are exhausted.
+	Stderr() <-chan string
+	// Wait blocks until the process exits and reports its outcome. The
+	// returned RunResult does not carry captured output; drain Stdout/Stderr
+	// for that while the process is running.
+	Wait() (*RunResult, error)
+	// Signal sends sig to the running process.
+	Signal(sig os.Signal) error
+}
+
+// CommandRunner runs *exec.Cmd commands. The default implementation,
+// ExecRunner, spawns real OS processes; tests can substitute a fake
+// implementation to exercise callers (e.g. RunCommand) without spawning
+// anything.
+type CommandRunner interface {
+	// Run starts command, waits for it to finish, and returns its fully
+	// captured stdout/stderr.
+	Run(ctx context.Context, command *exec.Cmd) (*RunResult, error)
+	// Start starts command and returns a Handle for interacting with it
+	// while it's still running.
+	Start(ctx context.Context, command *exec.Cmd) (Handle, error)
+	// CombinedOutput runs command and returns its interleaved stdout+stderr.
+	CombinedOutput(ctx context.Context, command *exec.Cmd) ([]byte, error)
+}
+
+// ExecRunner is the default, os/exec-backed CommandRunner.
+type ExecRunner struct{}
+
+// NewExecRunner returns the default CommandRunner, backed by os/exec.
+func NewExecRunner() *ExecRunner {
+	return &ExecRunner{}
+}
+
+// DefaultCommandRunner is the CommandRunner used by RunCommand/RunCommandWithOptions.
+// Tests may swap it for a fake runner to exercise those without spawning real processes.
+var DefaultCommandRunner CommandRunner = NewExecRunner()
+
+// commandLine joins command's argv into a single string for error messages and RunResult.
+func commandLine(command *exec.Cmd) string {
+	return strings.Join(command.Args, " ")
+}
+
+// Run starts command, waits for it to finish, and returns its fully captured stdout/stderr.
+func (r *ExecRunner) Run(ctx context.Context, command *exec.Cmd) (*RunResult, error) {
+	result := &RunResult{CommandLine: commandLine(command)}
+
+	var stdout, stderr bytes.Buffer
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	start := time.Now()
+	err := command.Run()
+	result.Duration = time.Since(start)
+	result.Stdout = stdout
+	result.Stderr = stderr
+	if command.ProcessState != nil {
+		result.ExitCode = command.ProcessState.ExitCode()
+	}
+
+	if err != nil {
+		return result, fmt.Errorf("command %q failed: %w", result.CommandLine, err)
+	}
+	return result, nil
+}
+
+// CombinedOutput runs command and returns its interleaved stdout+stderr.
+func (r *ExecRunner) CombinedOutput(ctx context.Context, command *exec.Cmd) ([]byte, error) {
+	// one shared buffer for both streams keeps lines in the order they were written
+	var combined bytes.Buffer
+	command.Stdout = &combined
+	command.Stderr = &combined
+	if err := command.Run(); err != nil {
+		return combined.Bytes(), fmt.Errorf("command %q failed: %w", commandLine(command), err)
+	}
+	return combined.Bytes(), nil
+}
+
+// Start starts command and returns a Handle for interacting with it while it's running.
+func (r *ExecRunner) Start(ctx context.Context, command *exec.Cmd) (Handle, error) {
+	stdin, err := command.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("could not open stdin pipe: %w", err)
+	}
+	stdoutPipe, err := command.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("could not open stdout pipe: %w", err)
+	}
+	stderrPipe, err := command.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("could not open stderr pipe: %w", err)
+	}
+
+	if err := command.Start(); err != nil {
+		return nil, fmt.Errorf("command %q failed to start: %w", commandLine(command), err)
+	}
+
+	h := &execHandle{
+		cmd:    command,
+		start:  time.Now(),
+		stdin:  stdin,
+		stdout: make(chan string, 16),
+		stderr: make(chan string, 16),
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go streamLines(stdoutPipe, h.stdout, &wg)
+	go streamLines(stderrPipe, h.stderr, &wg)
+	go func() {
+		wg.Wait()
+		close(h.stdout)
+		close(h.stderr)
+	}()
+
+	return h, nil
+}
+
+// streamLines copies r's lines onto out, closing the wait group's counter when r is exhausted.
+func streamLines(r io.Reader, out chan<- string, wg *sync.WaitGroup) {
+	defer wg.Done()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		out <- scanner.Text()
+	}
+}
+
+// execHandle is the ExecRunner's Handle implementation.
+type execHandle struct {
+	cmd    *exec.Cmd
+	start  time.Time
+	stdin  io.WriteCloser
+	stdout chan string
+	stderr chan string
+}
+
+func (h *execHandle) Stdin() io.WriteCloser { return h.stdin }
+func (h *execHandle) Stdout() <-chan string { return h.stdout }
+func (h *execHandle) Stderr() <-chan string { return h.stderr }
+
+func (h *execHandle) Wait() (*RunResult, error) {
+	err := h.cmd.Wait()
+	result := &RunResult{
+		CommandLine: commandLine(h.cmd),
+		Duration:    time.Since(h.start),
+	}
+	if h.cmd.ProcessState != nil {
+		result.ExitCode = h.cmd.ProcessState.ExitCode()
+	}
+	if err != nil {
+		return result, fmt.Errorf("command %q failed: %w", result.CommandLine, err)
+	}
+	return result, nil
+}
+
+func (h *execHandle) Signal(sig os.Signal) error {
+	if h.cmd.Process == nil {
+		return fmt.Errorf("process not started")
+	}
+	return h.cmd.Process.Signal(sig)
+}
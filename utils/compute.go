@@ -1,15 +1,94 @@
 package utils
 
 import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 )
 
+// ComputeBinaryPublicKey is the Ed25519 public key (hex-encoded) used to
+// verify `.sig` signatures over the downloaded compute binary's SHA-256
+// digest, when the release publishes one. Left blank until dkn-compute-node
+// starts signing its releases; signature verification is skipped when it's
+// empty, same as the bundled-catalog fallback in models.go.
+var ComputeBinaryPublicKey = ""
+
+// RequireComputeBinaryVerification switches verifyComputeBinary from
+// permissive to fail-closed: a missing `.sha256` (or, when
+// ComputeBinaryPublicKey is set, a missing `.sig`) companion file becomes a
+// hard error instead of a warning, so an attacker can't defeat verification
+// on an unattended/background upgrade simply by 404'ing those endpoints.
+// Set from --require-verification; defaults to false to preserve the
+// existing behavior for releases that don't publish every companion file.
+var RequireComputeBinaryVerification = false
+
+// GetLauncherLatestVersion fetches the latest released tag of the launcher
+// itself (firstbatchxyz/dkn-compute-launcher), so main can warn the user
+// when the binary they're running is out of date.
+//
+// Returns:
+//   - string: The latest launcher release tag (e.g. "v0.4.1").
+//   - error: An error if the request fails, or the response cannot be parsed.
+func GetLauncherLatestVersion() (string, error) {
+	url := "https://api.github.com/repos/firstbatchxyz/dkn-compute-launcher/releases/latest"
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get latest release, status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	tagName, ok := result["tag_name"].(string)
+	if !ok {
+		return "", fmt.Errorf("tag_name not found or not a string")
+	}
+
+	return tagName, nil
+}
+
+// IsNewVersionAvaliable reports whether a newer dkn-compute-node release is
+// available than currentVersion, checking the latest stable release tag via
+// GetComputeLatestTag.
+//
+// Parameters:
+//   - currentVersion: the currently running/installed dkn-compute version tag.
+//
+// Returns:
+//   - bool: true if a newer version was found.
+//   - string: the newer version tag, or "" if none is available or the check failed.
+func IsNewVersionAvaliable(currentVersion string) (bool, string) {
+	latest, err := GetComputeLatestTag(true, false, false)
+	if err != nil {
+		return false, ""
+	}
+	if latest != currentVersion {
+		return true, latest
+	}
+	return false, ""
+}
+
 // GetComputeLatestTag fetches a specific tag from the DKN Compute Node repository on GitHub based on the provided parameters.
 // It can return the latest stable release, the latest development version, or the previous stable release.
 //
@@ -147,16 +226,19 @@ func GetSortedTags() ([]map[string]interface{}, error) {
 //   - version: The version of the binary to download (e.g., v0.2.4).
 //   - workingDir: The directory where the binary will be saved.
 //   - file: The name of the file to save the binary as.
+//   - verify: When true, the downloaded binary's SHA-256 (and signature, if the release publishes one and
+//     ComputeBinaryPublicKey is set) is checked against the release's companion files before it's made
+//     executable; a mismatch deletes the file and returns an error instead of letting a tampered binary run.
 //
 // Returns:
-//   - error: An error if the download, file preparation, or version retrieval fails.
+//   - error: An error if the download, verification, file preparation, or version retrieval fails.
 //
 // Behavior:
 //   - Constructs the download URL based on the provided version, operating system, and architecture.
 //   - If the specified version cannot be downloaded (e.g., due to a 404 error), the function attempts to download the previous stable version.
 //   - If the previous version download also fails, an error is returned.
 //   - After downloading, the function applies necessary permissions to the binary by calling `PrepareComputeBinary`.
-func DownloadLatestComputeBinary(version, workingDir, file string) error {
+func DownloadLatestComputeBinary(version, workingDir, file string, verify bool) error {
 	os, arch := GetOSAndArch()
 	extension := ""
 	if os == "windows" {
@@ -172,12 +254,12 @@ func DownloadLatestComputeBinary(version, workingDir, file string) error {
 			// if the release exists but the downloads responds with 404, it means the build didn't finished yet
 			// use the previous latest version
 			fmt.Println("Warning: The latest compute binaries are currently being built. Downloading the previous version. You can restart the launcher in ~20 minutes to run the latest version.")
-			version, err = GetComputeLatestTag(false, false, true)
+			version, err = NewReleaseResolver(workingDir).Previous("stable")
 			if err != nil {
 				return err
 			}
-			asset_name := fmt.Sprintf("dkn-compute-binary-%s-%s%s", os, arch, extension)
-			url := fmt.Sprintf("https://github.com/firstbatchxyz/dkn-compute-node/releases/download/%s/%s", version, asset_name)
+			asset_name = fmt.Sprintf("dkn-compute-binary-%s-%s%s", os, arch, extension)
+			url = fmt.Sprintf("https://github.com/firstbatchxyz/dkn-compute-node/releases/download/%s/%s", version, asset_name)
 			_, err = DownloadFile(url, destPath)
 			if err != nil {
 				// if its couldn't download the previous latest version, raise an error
@@ -189,6 +271,15 @@ func DownloadLatestComputeBinary(version, workingDir, file string) error {
 		}
 	}
 
+	if verify {
+		if err := verifyComputeBinary(destPath, url); err != nil {
+			if removeErr := removeFile(destPath); removeErr != nil {
+				return fmt.Errorf("binary verification failed (%w) and the partial file couldn't be removed: %s", err, removeErr)
+			}
+			return fmt.Errorf("refusing to run an unverified dkn-compute binary: %w", err)
+		}
+	}
+
 	// give the executable privledges etc.
 	if err := PrepareComputeBinary(workingDir, file); err != nil {
 		return err
@@ -197,6 +288,104 @@ func DownloadLatestComputeBinary(version, workingDir, file string) error {
 	return nil
 }
 
+// removeFile deletes the file at path; it's a thin wrapper around os.Remove
+// so callers whose local variables shadow the `os` package (e.g. the `os`
+// OS-name variable in DownloadLatestComputeBinary) can still reach it.
+func removeFile(path string) error {
+	return os.Remove(path)
+}
+
+// verifyComputeBinary checks the SHA-256 of the file at destPath against the
+// `<asset>.sha256` companion file published alongside assetURL on GitHub, and
+// (when ComputeBinaryPublicKey is set and the release publishes an
+// `<asset>.sig`) verifies an Ed25519 signature over that digest.
+//
+// A companion file that simply doesn't exist (HTTP 404, since dkn-compute-node
+// releases don't always publish one) is treated as "nothing to verify against"
+// and only prints a warning, matching the rest of the launcher's permissive
+// handling of optional integrity material, unless RequireComputeBinaryVerification
+// is set, in which case a missing companion file is a hard failure too (a
+// compromised mirror could otherwise defeat verification by 404'ing it). An
+// asset that exists but doesn't match is always a hard failure.
+func verifyComputeBinary(destPath, assetURL string) error {
+	fileBytes, err := os.ReadFile(destPath)
+	if err != nil {
+		return fmt.Errorf("failed to read downloaded binary for verification: %w", err)
+	}
+	digest := sha256.Sum256(fileBytes)
+	digestHex := hex.EncodeToString(digest[:])
+
+	checksumBody, found, err := fetchCompanionFile(assetURL + ".sha256")
+	if err != nil {
+		return fmt.Errorf("failed to fetch checksum file: %w", err)
+	}
+	if !found {
+		if RequireComputeBinaryVerification {
+			return fmt.Errorf("no .sha256 checksum published for this release asset, and --require-verification is set")
+		}
+		fmt.Println("Warning: no .sha256 checksum published for this release asset, skipping integrity check")
+	} else {
+		expected := strings.ToLower(strings.TrimSpace(strings.Fields(strings.TrimSpace(checksumBody))[0]))
+		if expected != digestHex {
+			return fmt.Errorf("sha256 mismatch: expected %s, got %s", expected, digestHex)
+		}
+	}
+
+	if ComputeBinaryPublicKey == "" {
+		return nil
+	}
+
+	sigBody, found, err := fetchCompanionFile(assetURL + ".sig")
+	if err != nil {
+		return fmt.Errorf("failed to fetch signature file: %w", err)
+	}
+	if !found {
+		if RequireComputeBinaryVerification {
+			return fmt.Errorf("no .sig signature published for this release asset, and --require-verification is set")
+		}
+		fmt.Println("Warning: no .sig signature published for this release asset, skipping signature check")
+		return nil
+	}
+
+	pubKeyBytes, err := hex.DecodeString(strings.TrimPrefix(ComputeBinaryPublicKey, "0x"))
+	if err != nil || len(pubKeyBytes) != ed25519.PublicKeySize {
+		return fmt.Errorf("ComputeBinaryPublicKey is not a valid %d-byte ed25519 key", ed25519.PublicKeySize)
+	}
+	signature, err := hex.DecodeString(strings.TrimSpace(sigBody))
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(pubKeyBytes), digest[:], signature) {
+		return fmt.Errorf("signature does not match the expected public key")
+	}
+
+	return nil
+}
+
+// fetchCompanionFile fetches a release companion file (e.g. a `.sha256` or
+// `.sig`). A 404 is reported via found=false rather than an error, since not
+// every release publishes every companion file.
+func fetchCompanionFile(url string) (body string, found bool, err error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", false, fmt.Errorf("unexpected status code %d fetching %s", resp.StatusCode, url)
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", false, err
+	}
+	return string(bodyBytes), true, nil
+}
+
 // PrepareComputeBinary grants execute privileges to the DKN Compute binary on Linux or macOS.
 //
 // Parameters:
@@ -0,0 +1,305 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JobState represents the lifecycle state of a job managed by the launcher daemon.
+type JobState string
+
+const (
+	JobStateStarting JobState = "starting"
+	JobStateRunning  JobState = "running"
+	JobStateStopped  JobState = "stopped"
+	JobStateCrashed  JobState = "crashed"
+)
+
+// Job represents a single dkn-compute child process managed by the daemon.
+// Each Job is driven by its own monitoring goroutine, mirroring the pattern
+// used by the foreground supervisor loop in main.go.
+type Job struct {
+	ID          string    `json:"id"`
+	Env         []string  `json:"-"`
+	PID         int       `json:"pid"`
+	State       JobState  `json:"state"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	LastRestart time.Time `json:"last_restart"`
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// jobLogFileName returns the per-job log file name a Job's process logs to,
+// so multiple jobs running under the same daemon don't clobber each other's
+// output (and "stream" can tail the right file).
+func jobLogFileName(id string) string {
+	return fmt.Sprintf("logs-%s.txt", id)
+}
+
+// Supervisor keeps a map of Jobs and is the single source of truth the
+// daemon's control API reads and mutates.
+type Supervisor struct {
+	mu      sync.Mutex
+	jobs    map[string]*Job
+	workDir string
+}
+
+// NewSupervisor creates an empty Supervisor rooted at workDir.
+func NewSupervisor(workDir string) *Supervisor {
+	return &Supervisor{jobs: make(map[string]*Job), workDir: workDir}
+}
+
+// SubmitJob starts a new dkn-compute child process for the given job id and
+// registers it with the supervisor. The process is launched with RunCommand,
+// logging to "logs-<id>.txt" so multiple jobs don't clobber each other.
+//
+// Returns:
+//   - *Job: the registered job, already running.
+//   - error: if a job with the same id already exists or the process fails to start.
+func (s *Supervisor) SubmitJob(id, execCommand string, env []string) (*Job, error) {
+	s.mu.Lock()
+	if _, exists := s.jobs[id]; exists {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("job %q already exists", id)
+	}
+	s.mu.Unlock()
+
+	pid, err := RunCommand(s.workDir, "file:"+jobLogFileName(id), false, 0, env, execCommand)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start job %q: %w", id, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	now := time.Now()
+	job := &Job{
+		ID:        id,
+		Env:       env,
+		PID:       pid,
+		State:     JobStateRunning,
+		CreatedAt: now,
+		UpdatedAt: now,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+
+	s.mu.Lock()
+	s.jobs[id] = job
+	s.mu.Unlock()
+
+	go s.monitor(job)
+
+	return job, nil
+}
+
+// monitor watches a running job and flips its state to crashed once the
+// underlying process disappears, the same polling approach used by the
+// foreground loop in main.go.
+func (s *Supervisor) monitor(job *Job) {
+	for {
+		time.Sleep(5 * time.Second)
+		select {
+		case <-job.ctx.Done():
+			return
+		default:
+			if !IsProcessRunning(job.PID) {
+				s.mu.Lock()
+				job.State = JobStateCrashed
+				job.UpdatedAt = time.Now()
+				s.mu.Unlock()
+				return
+			}
+		}
+	}
+}
+
+// ListJobs returns a snapshot of every job currently tracked by the supervisor.
+func (s *Supervisor) ListJobs() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, *job)
+	}
+	return jobs
+}
+
+// GetJob returns the job registered under id, if any.
+func (s *Supervisor) GetJob(id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// StopJob gracefully stops the job registered under id and removes it from
+// the supervisor.
+func (s *Supervisor) StopJob(id string) error {
+	s.mu.Lock()
+	job, ok := s.jobs[id]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("job %q not found", id)
+	}
+
+	job.cancel()
+	if err := StopProcess(job.PID); err != nil {
+		return fmt.Errorf("failed to stop job %q: %w", id, err)
+	}
+
+	s.mu.Lock()
+	job.State = JobStateStopped
+	job.UpdatedAt = time.Now()
+	delete(s.jobs, id)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// DaemonRequest is a single call made against the launcher daemon's local
+// control API, sent as a newline-delimited JSON object over the IPC socket.
+type DaemonRequest struct {
+	Action         string            `json:"action"` // one of: submit, list, status, stream, update, stop
+	JobID          string            `json:"job_id,omitempty"`
+	Env            map[string]string `json:"env,omitempty"`
+	CurrentVersion string            `json:"current_version,omitempty"` // for "update": the job's currently running dkn-compute version
+}
+
+// DaemonResponse is the reply to a DaemonRequest. "stream" sends one
+// DaemonResponse per output line instead of a single reply; every other
+// action sends exactly one.
+type DaemonResponse struct {
+	OK              bool   `json:"ok"`
+	Error           string `json:"error,omitempty"`
+	Jobs            []Job  `json:"jobs,omitempty"`
+	Output          string `json:"output,omitempty"`           // one line of job stdout/stderr, for "stream"
+	UpdateAvailable bool   `json:"update_available,omitempty"` // for "update"
+	LatestVersion   string `json:"latest_version,omitempty"`   // for "update"
+}
+
+// ServeDaemon listens on the platform-appropriate IPC endpoint (a Unix
+// socket on Linux/macOS, a named pipe on Windows, see SocketListen) and
+// dispatches DaemonRequests against sup until the listener is closed.
+func ServeDaemon(socketPath string, sup *Supervisor, execCommand string) error {
+	listener, err := SocketListen(socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %q: %w", socketPath, err)
+	}
+	defer listener.Close()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+		go handleDaemonConn(conn, sup, execCommand)
+	}
+}
+
+// handleDaemonConn serves DaemonRequests off a single IPC connection until
+// the client disconnects.
+func handleDaemonConn(conn net.Conn, sup *Supervisor, execCommand string) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req DaemonRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			encoder.Encode(DaemonResponse{OK: false, Error: fmt.Sprintf("invalid request: %s", err)})
+			continue
+		}
+		// "stream" keeps pushing output lines for as long as the job runs,
+		// instead of the single-reply-per-request flow every other action
+		// uses, so it's handled separately from dispatchDaemonRequest. The
+		// connection is closed once the stream ends, which is how the client
+		// (StreamDaemonRequest) learns that the stream is over.
+		if req.Action == "stream" {
+			streamJobLogs(encoder, sup, req.JobID)
+			return
+		}
+		encoder.Encode(dispatchDaemonRequest(sup, execCommand, req))
+	}
+}
+
+// streamJobLogs tails job id's log file (see jobLogFileName), sending one
+// DaemonResponse per line as it's appended, until the job is no longer
+// running or the client disconnects (a write failure on encoder ends it).
+func streamJobLogs(encoder *json.Encoder, sup *Supervisor, id string) {
+	job, ok := sup.GetJob(id)
+	if !ok {
+		encoder.Encode(DaemonResponse{OK: false, Error: fmt.Sprintf("job %q not found", id)})
+		return
+	}
+
+	logPath := filepath.Join(sup.workDir, jobLogFileName(id))
+	file, err := os.Open(logPath)
+	if err != nil {
+		encoder.Encode(DaemonResponse{OK: false, Error: fmt.Sprintf("failed to open job log: %s", err)})
+		return
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if encErr := encoder.Encode(DaemonResponse{OK: true, Output: strings.TrimRight(line, "\n")}); encErr != nil {
+				return
+			}
+		}
+		if err != nil {
+			if !IsProcessRunning(job.PID) {
+				return
+			}
+			time.Sleep(250 * time.Millisecond)
+		}
+	}
+}
+
+// dispatchDaemonRequest executes a single DaemonRequest against sup.
+func dispatchDaemonRequest(sup *Supervisor, execCommand string, req DaemonRequest) DaemonResponse {
+	switch req.Action {
+	case "submit":
+		if req.JobID == "" {
+			return DaemonResponse{OK: false, Error: "job_id is required"}
+		}
+		job, err := sup.SubmitJob(req.JobID, execCommand, MapToList(req.Env))
+		if err != nil {
+			return DaemonResponse{OK: false, Error: err.Error()}
+		}
+		return DaemonResponse{OK: true, Jobs: []Job{*job}}
+	case "list":
+		return DaemonResponse{OK: true, Jobs: sup.ListJobs()}
+	case "status":
+		job, ok := sup.GetJob(req.JobID)
+		if !ok {
+			return DaemonResponse{OK: false, Error: fmt.Sprintf("job %q not found", req.JobID)}
+		}
+		return DaemonResponse{OK: true, Jobs: []Job{job}}
+	case "update":
+		newVersionAvailable, newVersion := IsNewVersionAvaliable(req.CurrentVersion)
+		return DaemonResponse{OK: true, UpdateAvailable: newVersionAvailable, LatestVersion: newVersion}
+	case "stop":
+		if err := sup.StopJob(req.JobID); err != nil {
+			return DaemonResponse{OK: false, Error: err.Error()}
+		}
+		return DaemonResponse{OK: true}
+	default:
+		return DaemonResponse{OK: false, Error: fmt.Sprintf("unknown action %q", req.Action)}
+	}
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+)
+
+// DialDaemon connects to a running launcher daemon's IPC endpoint at
+// socketPath, wrapping the platform-specific SocketDial with a hint for the
+// common failure mode (no daemon running).
+func DialDaemon(socketPath string) (net.Conn, error) {
+	conn, err := SocketDial(socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to launcher daemon at %q (is `launcher daemon` running?): %w", socketPath, err)
+	}
+	return conn, nil
+}
+
+// SendDaemonRequest sends a single DaemonRequest over conn and reads back the
+// single DaemonResponse every action except "stream" replies with.
+func SendDaemonRequest(conn net.Conn, req DaemonRequest) (DaemonResponse, error) {
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return DaemonResponse{}, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	var resp DaemonResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return DaemonResponse{}, fmt.Errorf("failed to read response: %w", err)
+	}
+	return resp, nil
+}
+
+// StreamDaemonRequest sends a "stream" DaemonRequest over conn and calls
+// onLine for every output line the daemon pushes back, until the job stops
+// (the daemon closes the connection) or onLine returns an error.
+func StreamDaemonRequest(conn net.Conn, req DaemonRequest, onLine func(line string) error) error {
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	decoder := json.NewDecoder(conn)
+	for {
+		var resp DaemonResponse
+		if err := decoder.Decode(&resp); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		if !resp.OK {
+			return fmt.Errorf("%s", resp.Error)
+		}
+		if err := onLine(resp.Output); err != nil {
+			return err
+		}
+	}
+}
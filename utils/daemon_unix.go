@@ -0,0 +1,30 @@
+//go:build darwin || linux
+// +build darwin linux
+
+package utils
+
+import (
+	"net"
+	"os"
+)
+
+// SocketListen opens the launcher daemon's IPC endpoint on Linux/macOS,
+// a Unix domain socket at socketPath. Any stale socket file left behind by
+// a previous, uncleanly terminated daemon is removed first.
+func SocketListen(socketPath string) (net.Listener, error) {
+	if FileExists(socketPath) {
+		os.Remove(socketPath)
+	}
+	return net.Listen("unix", socketPath)
+}
+
+// DefaultSocketPath returns the default path of the daemon's Unix socket
+// inside the given working directory.
+func DefaultSocketPath(workingDir string) string {
+	return workingDir + "/dkn-launcher.sock"
+}
+
+// SocketDial connects to a running daemon's Unix domain socket at socketPath.
+func SocketDial(socketPath string) (net.Conn, error) {
+	return net.Dial("unix", socketPath)
+}
@@ -0,0 +1,37 @@
+//go:build windows
+// +build windows
+
+package utils
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/Microsoft/go-winio"
+)
+
+// SocketListen opens the launcher daemon's IPC endpoint on Windows, a named
+// pipe at socketPath (e.g. `\\.\pipe\dkn-launcher`).
+func SocketListen(socketPath string) (net.Listener, error) {
+	listener, err := winio.ListenPipe(socketPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on named pipe: %w", err)
+	}
+	return listener, nil
+}
+
+// DefaultSocketPath returns the default named pipe path used by the daemon.
+// The working directory is ignored since named pipes live in their own
+// namespace on Windows.
+func DefaultSocketPath(workingDir string) string {
+	return `\\.\pipe\dkn-launcher`
+}
+
+// SocketDial connects to a running daemon's named pipe at socketPath.
+func SocketDial(socketPath string) (net.Conn, error) {
+	conn, err := winio.DialPipe(socketPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial named pipe: %w", err)
+	}
+	return conn, nil
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// DeathWatcherDefaultDeadline bounds how long a DeathWatcher waits for
+// tracked processes to exit gracefully before force-killing stragglers.
+const DeathWatcherDefaultDeadline = 30 * time.Second
+
+// doubleSignalWindow is how soon a second shutdown signal has to arrive
+// after the first for DeathWatcher to escalate straight to killing every
+// tracked process, skipping shutdown hooks and the grace period entirely.
+const doubleSignalWindow = 2 * time.Second
+
+// DeathWatcher gives the launcher the "clean death" semantics common in
+// daemon frameworks: it installs handlers for the platform's shutdown
+// signals (SIGTERM/SIGINT/SIGHUP on Unix, Ctrl-C on Windows), tracks every
+// PID started via RunCommand, and on signal runs registered shutdown hooks
+// before stopping tracked processes within a bounded deadline. A second
+// signal within doubleSignalWindow skips straight to killing everything, so
+// an impatient Ctrl-C twice always gets the process to exit.
+type DeathWatcher struct {
+	mu          sync.Mutex
+	pids        map[int]bool
+	shutdowns   []func(ctx context.Context) error
+	deadline    time.Duration
+	firstSignal time.Time
+
+	sigCh chan os.Signal
+}
+
+// NewDeathWatcher creates a DeathWatcher that waits up to deadline for
+// tracked processes to stop gracefully before force-killing stragglers. A
+// non-positive deadline falls back to DeathWatcherDefaultDeadline.
+func NewDeathWatcher(deadline time.Duration) *DeathWatcher {
+	if deadline <= 0 {
+		deadline = DeathWatcherDefaultDeadline
+	}
+	return &DeathWatcher{
+		pids:     make(map[int]bool),
+		deadline: deadline,
+		sigCh:    make(chan os.Signal, 2),
+	}
+}
+
+// DefaultDeathWatcher is the DeathWatcher that RunCommand/RunCommandWithOptions
+// register every started PID with, so a single Watch call at startup covers
+// the whole process tree the launcher spawns.
+var DefaultDeathWatcher = NewDeathWatcher(DeathWatcherDefaultDeadline)
+
+// Track registers pid so a future shutdown signal also stops it.
+func (w *DeathWatcher) Track(pid int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pids[pid] = true
+}
+
+// Untrack removes pid, e.g. once RunCommand observes it has already exited
+// on its own, so shutdown doesn't try to stop a PID that's long gone (and
+// may since have been reused by an unrelated process).
+func (w *DeathWatcher) Untrack(pid int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.pids, pid)
+}
+
+// OnShutdown registers fn to run during a graceful shutdown (flush logs,
+// persist wallet state, deregister from the P2P network, ...). Registered
+// closures run in LIFO order, mirroring defer, so a subsystem that depends
+// on an earlier one gets to tear down first.
+func (w *DeathWatcher) OnShutdown(fn func(ctx context.Context) error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.shutdowns = append(w.shutdowns, fn)
+}
+
+// Watch installs the platform's shutdown signal handlers and loops reading
+// them for the rest of the process' life: the first signal kicks off a
+// graceful shutdown in the background (so a second signal within
+// doubleSignalWindow can still be observed and escalate to an immediate
+// kill), and either path ends the process via os.Exit. Callers run it in
+// its own goroutine.
+func (w *DeathWatcher) Watch() {
+	signal.Notify(w.sigCh, shutdownSignals...)
+
+	for sig := range w.sigCh {
+		now := time.Now()
+		w.mu.Lock()
+		isSecond := !w.firstSignal.IsZero() && now.Sub(w.firstSignal) <= doubleSignalWindow
+		if w.firstSignal.IsZero() {
+			w.firstSignal = now
+		}
+		w.mu.Unlock()
+
+		if isSecond {
+			fmt.Printf("Received a second %s within %s, force-killing everything\n", sig, doubleSignalWindow)
+			w.killAll()
+			os.Exit(1)
+		}
+
+		fmt.Printf("Received %s, shutting down gracefully (send again within %s to force)\n", sig, doubleSignalWindow)
+		// run asynchronously so Watch keeps reading sigCh and can still catch
+		// a second signal (and escalate) while this graceful shutdown is in flight
+		go func() {
+			w.shutdown()
+			os.Exit(0)
+		}()
+	}
+}
+
+// shutdown runs every registered OnShutdown closure (LIFO), then stops every
+// tracked process concurrently within w.deadline, escalating each one from
+// SIGTERM to SIGKILL per StopProcessCtx if it doesn't exit in time.
+func (w *DeathWatcher) shutdown() {
+	w.mu.Lock()
+	shutdowns := append([]func(ctx context.Context) error{}, w.shutdowns...)
+	pids := w.pidList()
+	w.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.deadline)
+	defer cancel()
+
+	for i := len(shutdowns) - 1; i >= 0; i-- {
+		if err := shutdowns[i](ctx); err != nil {
+			fmt.Printf("Warning: shutdown hook failed: %s\n", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, pid := range pids {
+		wg.Add(1)
+		go func(pid int) {
+			defer wg.Done()
+			if err := StopProcessCtx(ctx, pid, w.deadline, true); err != nil {
+				fmt.Printf("Warning: failed to stop process %d: %s\n", pid, err)
+			}
+		}(pid)
+	}
+	wg.Wait()
+}
+
+// killAll immediately force-kills every tracked process' whole group/tree,
+// skipping shutdown hooks and the grace period entirely.
+func (w *DeathWatcher) killAll() {
+	w.mu.Lock()
+	pids := w.pidList()
+	w.mu.Unlock()
+
+	for _, pid := range pids {
+		forceKillGroup(pid)
+	}
+}
+
+// pidList snapshots the currently tracked PIDs. Callers must hold w.mu.
+func (w *DeathWatcher) pidList() []int {
+	pids := make([]int, 0, len(w.pids))
+	for pid := range w.pids {
+		pids = append(pids, pid)
+	}
+	return pids
+}
@@ -3,9 +3,156 @@ package utils
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
+// DockerComposeOptions describes everything needed to render the
+// docker-compose.yml for a `--docker` deployment of dkn-compute.
+type DockerComposeOptions struct {
+	ComputeVersion string            // image tag to pin the dkn-compute service to
+	Registry       string            // image registry override, e.g. "ghcr.io/firstbatchxyz"
+	Platform       string            // "linux/amd64" or "linux/arm64", empty lets Docker decide
+	Envvars        map[string]string // env passed through to the dkn-compute container
+	UseOllama      bool              // whether to also run a local "ollama" service
+	OllamaPort     string            // host port the ollama service is published on
+}
+
+// DockerComposeFileName is the name of the compose file generated under the
+// launcher's working directory for `--docker` deployments.
+const DockerComposeFileName = "docker-compose.yml"
+
+// GenerateDockerComposeFile renders a docker-compose.yml for running
+// dkn-compute (and, optionally, a companion ollama service) under workingDir,
+// pinned to the resolved opts.ComputeVersion tag.
+//
+// Returns:
+//   - error: if the file cannot be written.
+func GenerateDockerComposeFile(workingDir string, opts DockerComposeOptions) error {
+	registry := opts.Registry
+	if registry == "" {
+		registry = "ghcr.io/firstbatchxyz"
+	}
+
+	var b strings.Builder
+	b.WriteString("version: \"3.8\"\n\n")
+	b.WriteString("services:\n")
+	b.WriteString("  dkn-compute:\n")
+	fmt.Fprintf(&b, "    image: %s/dkn-compute-node:%s\n", registry, opts.ComputeVersion)
+	// container_name matches the service name, so IsContainerHealthy can inspect it as "dkn-compute"
+	b.WriteString("    container_name: dkn-compute\n")
+	if opts.Platform != "" {
+		fmt.Fprintf(&b, "    platform: %s\n", opts.Platform)
+	}
+	b.WriteString("    restart: unless-stopped\n")
+	b.WriteString("    networks:\n      - dkn-net\n")
+	b.WriteString("    environment:\n")
+
+	// sort keys for a stable, diffable compose file across regenerations
+	keys := make([]string, 0, len(opts.Envvars))
+	for key := range opts.Envvars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "      - %s=%s\n", key, opts.Envvars[key])
+	}
+	if opts.UseOllama {
+		b.WriteString("      - OLLAMA_HOST=http://ollama\n")
+		b.WriteString("      - OLLAMA_PORT=11434\n")
+	}
+
+	if opts.UseOllama {
+		port := opts.OllamaPort
+		if port == "" {
+			port = "11434"
+		}
+		b.WriteString("\n  ollama:\n")
+		b.WriteString("    image: ollama/ollama:latest\n")
+		if opts.Platform != "" {
+			fmt.Fprintf(&b, "    platform: %s\n", opts.Platform)
+		}
+		fmt.Fprintf(&b, "    ports:\n      - \"%s:11434\"\n", port)
+		b.WriteString("    restart: unless-stopped\n")
+		b.WriteString("    networks:\n      - dkn-net\n")
+	}
+
+	b.WriteString("\nnetworks:\n  dkn-net:\n    driver: bridge\n")
+
+	return os.WriteFile(filepath.Join(workingDir, DockerComposeFileName), []byte(b.String()), 0644)
+}
+
+// DockerComposePull pulls the images referenced by the generated
+// docker-compose.yml, honoring --platform/--registry overrides baked into
+// the file at generation time, using whichever compose command
+// CheckDockerComposeCommand detected.
+func DockerComposePull(workingDir string) error {
+	command, _, _ := CheckDockerComposeCommand()
+	pullArgs := []string{"pull"}
+	if command == "docker" {
+		pullArgs = append([]string{"compose"}, pullArgs...)
+	}
+	if _, err := RunCommand(workingDir, "stdout", true, 0, nil, command, pullArgs...); err != nil {
+		return fmt.Errorf("failed to pull docker compose images: %w", err)
+	}
+	return nil
+}
+
+// RunDockerComposeUp brings up the generated compose deployment using
+// whichever compose command CheckDockerComposeCommand detected.
+func RunDockerComposeUp(workingDir string) error {
+	command, upArgs, _ := CheckDockerComposeCommand()
+	if _, err := RunCommand(workingDir, "stdout", true, 0, nil, command, upArgs...); err != nil {
+		return fmt.Errorf("failed to bring up docker compose deployment: %w", err)
+	}
+	return nil
+}
+
+// RunDockerComposeDown tears down the generated compose deployment using
+// whichever compose command CheckDockerComposeCommand detected.
+func RunDockerComposeDown(workingDir string) error {
+	command, _, downArgs := CheckDockerComposeCommand()
+	if _, err := RunCommand(workingDir, "stdout", true, 0, nil, command, downArgs...); err != nil {
+		return fmt.Errorf("failed to bring down docker compose deployment: %w", err)
+	}
+	return nil
+}
+
+// IsContainerHealthy polls `docker inspect` for the given container/service
+// name and reports whether it's running, in place of IsProcessRunning for
+// native deployments. A container without a configured HEALTHCHECK is
+// considered healthy as soon as it's running.
+func IsContainerHealthy(containerName string) bool {
+	status, err := dockerInspect(containerName, "{{.State.Health.Status}}")
+	if err == nil && status != "" && status != "<no value>" {
+		return status == "healthy"
+	}
+
+	running, err := dockerInspect(containerName, "{{.State.Running}}")
+	if err != nil {
+		return false
+	}
+	return running == "true"
+}
+
+// dockerInspect runs `docker inspect -f <format> <container>` and returns
+// the trimmed output. Unlike RunCommand, which is built for long-running
+// processes, this needs the command's stdout captured directly.
+func dockerInspect(container, format string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "docker", "inspect", "-f", format, container).Output()
+	if err != nil {
+		return "", fmt.Errorf("docker inspect failed: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 // CheckDockerComposeCommand checks whether the system has Docker Compose installed and returns
 // the appropriate command and arguments for starting and stopping Docker containers.
 //
@@ -17,12 +164,12 @@ import (
 // Exits the program with a delay if neither Docker Compose nor docker-compose is installed.
 func CheckDockerComposeCommand() (string, []string, []string) {
 	// check docker compose
-	if _, err := RunCommand("", false, true, 0, nil, "docker", "compose", "version"); err == nil {
+	if _, err := RunCommand("", "none", true, 0, nil, "docker", "compose", "version"); err == nil {
 		return "docker", []string{"compose", "up", "-d"}, []string{"compose", "down"}
 	}
 
 	// check docker-compose
-	if _, err := RunCommand("", false, true, 0, nil, "docker-compose", "version"); err == nil {
+	if _, err := RunCommand("", "none", true, 0, nil, "docker-compose", "version"); err == nil {
 		return "docker-compose", []string{"up", "-d"}, []string{"down"}
 	}
 
@@ -38,7 +185,7 @@ func CheckDockerComposeCommand() (string, []string, []string) {
 // Returns:
 //   - bool: Returns true if Docker is running (i.e., "docker info" executes successfully), otherwise false.
 func IsDockerUp(timeout time.Duration) bool {
-	_, err := RunCommand("", false, true, timeout, nil, "docker", "info")
+	_, err := RunCommand("", "none", true, timeout, nil, "docker", "info")
 	if err != nil {
 		if err == context.DeadlineExceeded {
 			fmt.Println("Error: Docker did not respond within the expected time.")
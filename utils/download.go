@@ -0,0 +1,205 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	downloadMaxRetries     = 5
+	downloadInitialBackoff = 1 * time.Second
+	downloadPartSuffix     = ".part"
+	downloadMetaSuffix     = ".part.json"
+)
+
+// downloadState is the sidecar JSON persisted next to a `.part` file so an
+// interrupted download can resume from where it left off instead of
+// restarting from zero.
+type downloadState struct {
+	ETag         string `json:"etag"`
+	TotalSize    int64  `json:"total_size"`
+	BytesWritten int64  `json:"bytes_written"`
+}
+
+// Downloader downloads files with retries, exponential backoff, and HTTP
+// Range-based resume, persisting progress in a `<path>.part` file and a
+// `<path>.part.json` sidecar so flaky connections don't force a full
+// restart on every transient error.
+type Downloader struct {
+	MaxRetries     int
+	InitialBackoff time.Duration
+}
+
+// NewDownloader returns a Downloader configured with the package's default
+// retry count and backoff.
+func NewDownloader() *Downloader {
+	return &Downloader{MaxRetries: downloadMaxRetries, InitialBackoff: downloadInitialBackoff}
+}
+
+// Download fetches url and saves it to path, resuming from a previous
+// `<path>.part` file when one exists and the server supports it. It retries
+// up to MaxRetries times with exponential backoff before giving up.
+//
+// Returns:
+//   - int: The HTTP status code of the final response (200 or 206 on success), or the
+//     failing response's code. -1 for errors unrelated to an HTTP response.
+//   - error: Returns an error if every attempt fails, otherwise nil.
+func (d *Downloader) Download(url, path string) (int, error) {
+	partPath := path + downloadPartSuffix
+	metaPath := path + downloadMetaSuffix
+
+	var lastErr error
+	lastStatus := -1
+	backoff := d.InitialBackoff
+	for attempt := 0; attempt <= d.MaxRetries; attempt++ {
+		status, err := d.attempt(url, path, partPath, metaPath)
+		if err == nil {
+			return status, nil
+		}
+
+		lastErr = err
+		lastStatus = status
+		if attempt < d.MaxRetries {
+			fmt.Printf("Download attempt %d/%d failed (%s), retrying in %s...\n", attempt+1, d.MaxRetries+1, err, backoff)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+
+	return lastStatus, fmt.Errorf("download failed after %d attempts: %w", d.MaxRetries+1, lastErr)
+}
+
+// attempt performs a single download attempt, resuming from partPath/metaPath
+// when a matching partial download is found on disk.
+func (d *Downloader) attempt(url, path, partPath, metaPath string) (int, error) {
+	state := loadDownloadState(metaPath)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return -1, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	resuming := false
+	if state != nil && state.BytesWritten > 0 {
+		if info, statErr := os.Stat(partPath); statErr == nil && info.Size() == state.BytesWritten {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", state.BytesWritten))
+			if state.ETag != "" {
+				req.Header.Set("If-Range", state.ETag)
+			}
+			resuming = true
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return -1, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// either a fresh download, or the server ignored our range/ETag -
+		// either way we must start the part file over from scratch
+		resuming = false
+		os.Remove(partPath)
+	case http.StatusPartialContent:
+		if !resuming {
+			return resp.StatusCode, fmt.Errorf("server sent partial content for a non-range request")
+		}
+	default:
+		return resp.StatusCode, fmt.Errorf("bad status: %s", resp.Status)
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY
+	if resuming {
+		flags |= os.O_APPEND
+	} else {
+		flags |= os.O_TRUNC
+	}
+	out, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return -1, fmt.Errorf("failed to open part file: %w", err)
+	}
+
+	written, copyErr := io.Copy(out, resp.Body)
+	out.Close()
+
+	newState := &downloadState{ETag: resp.Header.Get("ETag"), TotalSize: responseTotalSize(resp)}
+	newState.BytesWritten = written
+	if resuming {
+		newState.BytesWritten += state.BytesWritten
+	}
+	saveDownloadState(metaPath, newState)
+
+	if copyErr != nil {
+		return -1, fmt.Errorf("failed to write to file: %w", copyErr)
+	}
+
+	if err := os.Rename(partPath, path); err != nil {
+		return -1, fmt.Errorf("failed to finalize downloaded file: %w", err)
+	}
+	os.Remove(metaPath)
+
+	return resp.StatusCode, nil
+}
+
+// responseTotalSize returns the full size of the resource being downloaded,
+// parsing it from the `Content-Range` header on a 206 response (whose
+// Content-Length is only the remaining bytes) or falling back to
+// Content-Length for a plain 200 response.
+func responseTotalSize(resp *http.Response) int64 {
+	if cr := resp.Header.Get("Content-Range"); cr != "" {
+		if idx := strings.LastIndex(cr, "/"); idx != -1 {
+			if total, err := strconv.ParseInt(cr[idx+1:], 10, 64); err == nil {
+				return total
+			}
+		}
+	}
+	return resp.ContentLength
+}
+
+// loadDownloadState reads the sidecar JSON at metaPath, returning nil if it
+// doesn't exist or can't be parsed (treated as "nothing to resume from").
+func loadDownloadState(metaPath string) *downloadState {
+	body, err := os.ReadFile(metaPath)
+	if err != nil {
+		return nil
+	}
+	var state downloadState
+	if err := json.Unmarshal(body, &state); err != nil {
+		return nil
+	}
+	return &state
+}
+
+// saveDownloadState persists state as the sidecar JSON at metaPath. Failures
+// are non-fatal: worst case, the next attempt re-downloads from zero.
+func saveDownloadState(metaPath string, state *downloadState) {
+	body, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+	os.WriteFile(metaPath, body, 0644)
+}
+
+// DownloadFile downloads a file from the specified URL and saves it to the specified path,
+// resuming from a previous partial download when possible. It's a thin wrapper around a
+// default Downloader for callers that don't need custom retry/backoff settings.
+//
+// Parameters:
+//   - url: The URL from which to download the file.
+//   - path: The local file path where the downloaded file will be saved.
+//
+// Returns:
+//   - int: The HTTP response status code if the download is successful or the specific response code if a failure occurs.
+//     If the error is unrelated to the HTTP response (e.g., file creation error), it returns -1.
+//   - error: Returns an error if the download, HTTP response, or file writing fails; otherwise, returns nil.
+func DownloadFile(url, path string) (int, error) {
+	return NewDownloader().Download(url, path)
+}
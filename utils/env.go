@@ -2,8 +2,6 @@ package utils
 
 import (
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -68,18 +66,22 @@ func LoadEnv(working_dir string) (map[string]string, error) {
 }
 
 // CheckRequiredEnvVars checks if the required environment variables are set in the provided map pointer.
-// If `DKN_WALLET_SECRET_KEY` is not set, it prompts the user to input it interactively.
+// If `DKN_WALLET_SECRET_KEY` is not set in the .env file, it's resolved via
+// ResolveDknSecretKey instead (OS keyring, DKN_WALLET_SECRET_KEY itself,
+// encrypted file, or a masked stdin prompt, per secretSource).
 // If `DKN_ADMIN_PUBLIC_KEY` is not set, it sets it to the provided default value.
 //
 // Parameters:
 //   - envvars: A pointer to a map of environment variables to check and update.
 //   - default_admin_pkey: The default admin public key to use if `DKN_ADMIN_PUBLIC_KEY` is not set.
-func CheckRequiredEnvVars(envvars *map[string]string, default_admin_pkey string) {
+//   - secretSource: The --secret-source value (keyring|env|file|stdin), or "" to try them in order.
+//   - workingDir: Working directory, used by the file secret provider.
+func CheckRequiredEnvVars(envvars *map[string]string, default_admin_pkey, secretSource, workingDir string) {
 	if (*envvars)["DKN_WALLET_SECRET_KEY"] == "" {
-		fmt.Println("DKN_WALLET_SECRET_KEY env-var is not set, getting it interactively")
-		skey, err := GetDknSecretKey()
+		fmt.Println("DKN_WALLET_SECRET_KEY env-var is not set, resolving it via secret provider")
+		skey, err := ResolveDknSecretKey(secretSource, workingDir)
 		if err != nil {
-			fmt.Printf("Error during user input: %s\n", err)
+			fmt.Printf("Error resolving secret key: %s\n", err)
 			ExitWithDelay(1)
 		}
 		(*envvars)["DKN_WALLET_SECRET_KEY"] = skey
@@ -106,46 +108,6 @@ func FileExists(parts ...string) bool {
 	return !info.IsDir()
 }
 
-// DownloadFile downloads a file from the specified URL and saves it to the specified path.
-// It returns the HTTP response status code and an error if any issue occurs during the download or file writing process.
-//
-// Parameters:
-//   - url: The URL from which to download the file.
-//   - path: The local file path where the downloaded file will be saved.
-//
-// Returns:
-//   - int: The HTTP response status code if the download is successful or the specific response code if a failure occurs.
-//     If the error is unrelated to the HTTP response (e.g., file creation error), it returns -1.
-//   - error: Returns an error if the download, HTTP response, or file writing fails; otherwise, returns nil.
-func DownloadFile(url, path string) (int, error) {
-	resp, err := http.Get(url)
-	// use -1 for errors unrelated to http response
-	response_status_code := -1
-	if err != nil {
-		return response_status_code, fmt.Errorf("failed to download file: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		response_status_code = resp.StatusCode
-		return response_status_code, fmt.Errorf("bad status: %s", resp.Status)
-	}
-
-	// create the file
-	out, err := os.Create(path)
-	if err != nil {
-		return -1, fmt.Errorf("failed to create file: %v", err)
-	}
-	defer out.Close()
-
-	// write the body to file
-	_, err = io.Copy(out, resp.Body)
-	if err != nil {
-		return -1, fmt.Errorf("failed to write to file: %v", err)
-	}
-	return 200, nil
-}
-
 // FetchEnvFileFromDknRepo downloads the .env example file from the DKN GitHub repository
 // and loads its contents into a map of environment variables.
 //
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// HealthCheckStabilizationPeriod is how long WaitForHealthy waits for a
+// process to keep running before considering it healthy when neither a
+// health port nor a readiness file is configured.
+const HealthCheckStabilizationPeriod = 3 * time.Second
+
+// WaitForHealthy blocks until the process identified by pid reports healthy,
+// or returns an error once timeout elapses.
+//
+// Health is determined by, in order of preference:
+//   - a successful TCP connect to 127.0.0.1:healthPort, when healthPort != 0.
+//   - the existence of readinessPath, a file the binary is expected to touch
+//     once ready, when readinessPath != "".
+//   - otherwise, the process simply staying alive for HealthCheckStabilizationPeriod.
+//
+// Parameters:
+//   - pid: the PID of the process being probed; the probe fails immediately if it exits.
+//   - healthPort: a TCP port to dial for a liveness check, or 0 to skip it.
+//   - readinessPath: a file path to poll for existence, or "" to skip it.
+//   - timeout: the overall deadline to wait for the process to become healthy.
+//
+// Returns:
+//   - error: non-nil if the process exits early or doesn't become healthy in time.
+func WaitForHealthy(pid int, healthPort int, readinessPath string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	startedAt := time.Now()
+
+	for time.Now().Before(deadline) {
+		if !IsProcessRunning(pid) {
+			return fmt.Errorf("process %d exited before becoming healthy", pid)
+		}
+
+		switch {
+		case healthPort != 0:
+			conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", healthPort), time.Second)
+			if err == nil {
+				conn.Close()
+				return nil
+			}
+		case readinessPath != "":
+			if FileExists(readinessPath) {
+				return nil
+			}
+		default:
+			if time.Since(startedAt) >= HealthCheckStabilizationPeriod {
+				return nil
+			}
+		}
+
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	return fmt.Errorf("process %d did not become healthy within %s", pid, timeout)
+}
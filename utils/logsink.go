@@ -0,0 +1,350 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogOptions configures the log sink used by RunCommandWithOptions when
+// outputDest is "sink". Only the fields relevant to the chosen sink need to
+// be set; the rest are ignored.
+type LogOptions struct {
+	// SinkPath is the log file name (relative to the command's working dir)
+	// used by the default rotating-file sink. Defaults to "logs.txt".
+	SinkPath string
+	// MaxSizeMB rotates the active file once it exceeds this size. 0 disables size-based rotation.
+	MaxSizeMB int
+	// MaxBackups is the number of rotated files to keep. 0 keeps all of them.
+	MaxBackups int
+	// Compress gzips rotated backup files.
+	Compress bool
+	// JSONLines re-encodes every line as a JSON object with a timestamp and
+	// a severity classified by stream (stdout -> "info", stderr -> "error").
+	JSONLines bool
+	// SyslogTag, when set, ships lines to the local syslog/journald under this
+	// tag instead of to a file. Not supported on Windows.
+	SyslogTag string
+	// RemoteURL, when set, ships batched JSON lines to a network log
+	// endpoint (e.g. a Loki push API or an OTLP/HTTP logs collector) instead
+	// of writing to a local file.
+	RemoteURL string
+	// Labels are static labels/attributes attached to every shipped line,
+	// used by the JSON-lines and remote sinks.
+	Labels map[string]string
+}
+
+// LogSink receives the lines written by a running command's stdout/stderr.
+type LogSink interface {
+	// WriteLine handles a single line of output. stderr is true when the
+	// line came from the command's stderr stream rather than its stdout.
+	WriteLine(line string, stderr bool) error
+	// Close flushes and releases any resources held by the sink.
+	Close() error
+}
+
+// buildLogSink constructs the LogSink described by opts: a remote sink if
+// RemoteURL is set, a syslog sink if SyslogTag is set, otherwise a rotating
+// file sink (optionally wrapped to emit JSON lines).
+func buildLogSink(workingDir string, opts *LogOptions) (LogSink, error) {
+	if opts == nil {
+		opts = &LogOptions{}
+	}
+
+	if opts.RemoteURL != "" {
+		return NewRemoteSink(opts.RemoteURL, opts.Labels), nil
+	}
+
+	if opts.SyslogTag != "" {
+		return NewSyslogSink(opts.SyslogTag)
+	}
+
+	sinkPath := opts.SinkPath
+	if sinkPath == "" {
+		sinkPath = "logs.txt"
+	}
+	fileSink, err := NewRotatingFileSink(filepath.Join(workingDir, sinkPath), *opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.JSONLines {
+		return NewJSONLinesSink(fileSink, opts.Labels), nil
+	}
+	return fileSink, nil
+}
+
+// logSinkWriter adapts a LogSink to an io.Writer by splitting writes on line
+// boundaries, so a LogSink can be attached directly to an *exec.Cmd's
+// Stdout/Stderr.
+type logSinkWriter struct {
+	sink   LogSink
+	stderr bool
+}
+
+func (w *logSinkWriter) Write(p []byte) (int, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(p))
+	for scanner.Scan() {
+		if err := w.sink.WriteLine(scanner.Text(), w.stderr); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// RotatingFileSink is a LogSink that writes lines to a file, rotating it
+// (lumberjack-style) once it exceeds MaxSizeMB, optionally gzip-compressing
+// rotated backups and pruning old ones beyond MaxBackups.
+type RotatingFileSink struct {
+	mu   sync.Mutex
+	path string
+	opts LogOptions
+	file *os.File
+	size int64
+}
+
+// NewRotatingFileSink opens (or creates) the log file at path, ready to
+// accept lines and rotate per opts.
+func NewRotatingFileSink(path string, opts LogOptions) (*RotatingFileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	size := int64(0)
+	if info, statErr := file.Stat(); statErr == nil {
+		size = info.Size()
+	}
+
+	return &RotatingFileSink{path: path, opts: opts, file: file, size: size}, nil
+}
+
+// WriteLine appends line to the active file, rotating first if it's grown
+// past MaxSizeMB.
+func (s *RotatingFileSink) WriteLine(line string, stderr bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.opts.MaxSizeMB > 0 && s.size >= int64(s.opts.MaxSizeMB)*1024*1024 {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := fmt.Fprintln(s.file, line)
+	s.size += int64(n)
+	return err
+}
+
+// rotate closes the active file, renames it with a timestamp suffix
+// (optionally gzipping it), prunes old backups beyond MaxBackups, and opens
+// a fresh file at the original path.
+func (s *RotatingFileSink) rotate() error {
+	s.file.Close()
+
+	rotatedPath := fmt.Sprintf("%s.%s", s.path, time.Now().UTC().Format("20060102T150405"))
+	if err := os.Rename(s.path, rotatedPath); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	if s.opts.Compress {
+		if err := gzipFile(rotatedPath); err == nil {
+			os.Remove(rotatedPath)
+		}
+	}
+
+	s.pruneBackups()
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to reopen log file after rotation: %w", err)
+	}
+	s.file = file
+	s.size = 0
+	return nil
+}
+
+// pruneBackups deletes the oldest rotated backups until at most MaxBackups remain.
+func (s *RotatingFileSink) pruneBackups() {
+	if s.opts.MaxBackups <= 0 {
+		return
+	}
+
+	dir := filepath.Dir(s.path)
+	base := filepath.Base(s.path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), base+".") {
+			backups = append(backups, entry.Name())
+		}
+	}
+	sort.Strings(backups)
+	for len(backups) > s.opts.MaxBackups {
+		os.Remove(filepath.Join(dir, backups[0]))
+		backups = backups[1:]
+	}
+}
+
+// Close closes the active log file.
+func (s *RotatingFileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// gzipFile compresses the file at path into path+".gz".
+func gzipFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(path + ".gz")
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gzWriter := gzip.NewWriter(out)
+	defer gzWriter.Close()
+
+	_, err = io.Copy(gzWriter, in)
+	return err
+}
+
+// jsonLineEntry is a single structured log line written by JSONLinesSink.
+type jsonLineEntry struct {
+	Time     string            `json:"time"`
+	Severity string            `json:"severity"`
+	Message  string            `json:"message"`
+	Labels   map[string]string `json:"labels,omitempty"`
+}
+
+// JSONLinesSink wraps another LogSink, re-encoding every line as a JSON
+// object with a timestamp and a severity classified by stream: stderr lines
+// become "error", stdout lines become "info".
+type JSONLinesSink struct {
+	inner  LogSink
+	labels map[string]string
+}
+
+// NewJSONLinesSink wraps inner so every line passed to it is JSON-encoded first.
+func NewJSONLinesSink(inner LogSink, labels map[string]string) *JSONLinesSink {
+	return &JSONLinesSink{inner: inner, labels: labels}
+}
+
+func (s *JSONLinesSink) WriteLine(line string, stderr bool) error {
+	severity := "info"
+	if stderr {
+		severity = "error"
+	}
+
+	body, err := json.Marshal(jsonLineEntry{
+		Time:     time.Now().UTC().Format(time.RFC3339Nano),
+		Severity: severity,
+		Message:  line,
+		Labels:   s.labels,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode log line as json: %w", err)
+	}
+	return s.inner.WriteLine(string(body), stderr)
+}
+
+func (s *JSONLinesSink) Close() error {
+	return s.inner.Close()
+}
+
+// RemoteSink ships log lines to a network log endpoint (e.g. a Loki push API
+// or an OTLP/HTTP logs collector) as small batched JSON POSTs, buffering
+// lines in memory and flushing on an interval so a slow or flaky network
+// connection doesn't block the command being logged.
+type RemoteSink struct {
+	mu     sync.Mutex
+	url    string
+	labels map[string]string
+	buffer []string
+	client http.Client
+	done   chan struct{}
+}
+
+// remoteSinkFlushInterval is how often RemoteSink ships its buffered lines.
+const remoteSinkFlushInterval = 5 * time.Second
+
+// NewRemoteSink starts a RemoteSink that POSTs batches of buffered lines to url.
+func NewRemoteSink(url string, labels map[string]string) *RemoteSink {
+	sink := &RemoteSink{
+		url:    url,
+		labels: labels,
+		client: http.Client{Timeout: 5 * time.Second},
+		done:   make(chan struct{}),
+	}
+	go sink.flushLoop()
+	return sink
+}
+
+func (s *RemoteSink) WriteLine(line string, stderr bool) error {
+	s.mu.Lock()
+	s.buffer = append(s.buffer, line)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *RemoteSink) flushLoop() {
+	ticker := time.NewTicker(remoteSinkFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			s.flush()
+			return
+		case <-ticker.C:
+			s.flush()
+		}
+	}
+}
+
+func (s *RemoteSink) flush() {
+	s.mu.Lock()
+	if len(s.buffer) == 0 {
+		s.mu.Unlock()
+		return
+	}
+	lines := s.buffer
+	s.buffer = nil
+	s.mu.Unlock()
+
+	body, err := json.Marshal(map[string]interface{}{"labels": s.labels, "lines": lines})
+	if err != nil {
+		return
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		fmt.Printf("Warning: failed to ship %d log line(s) to %s: %s\n", len(lines), s.url, err)
+		return
+	}
+	resp.Body.Close()
+}
+
+// Close flushes any buffered lines and stops the background flush loop.
+func (s *RemoteSink) Close() error {
+	close(s.done)
+	s.flush()
+	return nil
+}
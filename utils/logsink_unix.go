@@ -0,0 +1,31 @@
+//go:build darwin || linux
+
+package utils
+
+import "log/syslog"
+
+// SyslogSink ships log lines to the local syslog/journald under tag, using
+// the standard syslog protocol.
+type SyslogSink struct {
+	writer *syslog.Writer
+}
+
+// NewSyslogSink opens a connection to the local syslog daemon.
+func NewSyslogSink(tag string) (*SyslogSink, error) {
+	writer, err := syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogSink{writer: writer}, nil
+}
+
+func (s *SyslogSink) WriteLine(line string, stderr bool) error {
+	if stderr {
+		return s.writer.Err(line)
+	}
+	return s.writer.Info(line)
+}
+
+func (s *SyslogSink) Close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,18 @@
+//go:build windows
+
+package utils
+
+import "fmt"
+
+// SyslogSink is unavailable on Windows, which has no standard syslog
+// protocol; NewSyslogSink always errors so callers fall back to a file or
+// remote sink instead.
+type SyslogSink struct{}
+
+// NewSyslogSink always fails on Windows.
+func NewSyslogSink(tag string) (*SyslogSink, error) {
+	return nil, fmt.Errorf("syslog sink is not supported on windows")
+}
+
+func (s *SyslogSink) WriteLine(line string, stderr bool) error { return nil }
+func (s *SyslogSink) Close() error                             { return nil }
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ModelSelectionProfile is a non-interactive alternative to PickModels: a
+// YAML or JSON file listing the models to run per provider, so CI pipelines
+// and systemd units can start the launcher without a TTY to answer
+// PickModels' prompt.
+type ModelSelectionProfile struct {
+	OpenAI     []string `yaml:"openai" json:"openai"`
+	Gemini     []string `yaml:"gemini" json:"gemini"`
+	OpenRouter []string `yaml:"openrouter" json:"openrouter"`
+	Ollama     []string `yaml:"ollama" json:"ollama"`
+}
+
+// LoadModelSelectionProfile reads a ModelSelectionProfile from path, parsed
+// as JSON when path ends in ".json" and as YAML otherwise.
+//
+// Parameters:
+//   - path: path to the profile file.
+//
+// Returns:
+//   - ModelSelectionProfile: the parsed profile.
+//   - error: non-nil if the file couldn't be read or parsed.
+func LoadModelSelectionProfile(path string) (ModelSelectionProfile, error) {
+	var profile ModelSelectionProfile
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return profile, fmt.Errorf("failed to read models profile: %w", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.Unmarshal(body, &profile); err != nil {
+			return profile, fmt.Errorf("failed to parse models profile as json: %w", err)
+		}
+		return profile, nil
+	}
+
+	if err := yaml.Unmarshal(body, &profile); err != nil {
+		return profile, fmt.Errorf("failed to parse models profile as yaml: %w", err)
+	}
+	return profile, nil
+}
+
+// Models flattens the profile's per-provider lists into PickModels' comma-separated format.
+func (p ModelSelectionProfile) Models() string {
+	var all []string
+	all = append(all, p.OpenAI...)
+	all = append(all, p.Gemini...)
+	all = append(all, p.OpenRouter...)
+	all = append(all, p.Ollama...)
+	return strings.Join(all, ",")
+}
+
+// PickModelsFromProfile is PickModels' non-interactive counterpart: it loads
+// a ModelSelectionProfile from profilePath instead of prompting, validating
+// every OpenAI/Gemini entry against the provider's /models endpoint first so
+// a typo in the profile fails fast at startup instead of on the first job.
+//
+// Parameters:
+//   - profilePath: path to the YAML/JSON profile file.
+//   - openaiAPIKey: used to authenticate the OpenAI probe; skipped if empty.
+//   - geminiAPIKey: used to authenticate the Gemini probe; skipped if empty.
+//
+// Returns:
+//   - string: a comma-separated string of selected model names, in PickModels' format.
+//   - error: non-nil if the profile couldn't be loaded, or named a model that failed its probe.
+func PickModelsFromProfile(profilePath, openaiAPIKey, geminiAPIKey string) (string, error) {
+	profile, err := LoadModelSelectionProfile(profilePath)
+	if err != nil {
+		return "", err
+	}
+
+	for _, model := range profile.OpenAI {
+		if !ProbeRemoteModel("openai", model, openaiAPIKey) {
+			return "", fmt.Errorf("openai model %q from profile %s does not exist or is not accessible", model, profilePath)
+		}
+	}
+	for _, model := range profile.Gemini {
+		if !ProbeRemoteModel("gemini", model, geminiAPIKey) {
+			return "", fmt.Errorf("gemini model %q from profile %s does not exist or is not accessible", model, profilePath)
+		}
+	}
+
+	models := profile.Models()
+	if models == "" {
+		return "", fmt.Errorf("models profile %s does not list any models", profilePath)
+	}
+	return models, nil
+}
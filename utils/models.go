@@ -0,0 +1,215 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ModelCatalog holds the per-provider model lists that used to be hard-coded
+// in main.go. It's normally populated from a remote manifest so new models
+// become available to running launchers without a release.
+type ModelCatalog struct {
+	OpenAI     []string `json:"openai"`
+	Gemini     []string `json:"gemini"`
+	OpenRouter []string `json:"openrouter"`
+	Ollama     []string `json:"ollama"`
+}
+
+// modelsManifest is the on-the-wire shape of the hosted manifest: the
+// catalog payload plus an Ed25519 signature over its JSON-encoded bytes.
+type modelsManifest struct {
+	Models    ModelCatalog `json:"models"`
+	Signature string       `json:"signature"`
+}
+
+// DefaultModelsManifestURL is the default location of the signed model
+// manifest, hosted alongside the dkn-compute-node releases.
+const DefaultModelsManifestURL = "https://raw.githubusercontent.com/firstbatchxyz/dkn-compute-node/master/models.json"
+
+const (
+	modelsManifestCacheFile = "models-manifest.json"
+	modelsManifestETagFile  = "models-manifest.etag"
+)
+
+// FetchModelCatalog resolves the ModelCatalog to use for this run: it fetches
+// manifestURL (caching the response under workingDir with an ETag so
+// unchanged manifests don't re-download), verifies its Ed25519 signature
+// against adminPublicKey when possible, and falls back to the bundled
+// catalog whenever offline is true or the fetch/verification fails.
+//
+// Parameters:
+//   - workingDir: directory the manifest cache (body + ETag) is stored in.
+//   - manifestURL: URL of the signed manifest, overridable via --models-manifest.
+//   - offline: when true, skips the network entirely and uses the bundled catalog.
+//   - adminPublicKey: hex-encoded public key used to verify the manifest signature.
+//
+// Returns:
+//   - ModelCatalog: the resolved catalog; the bundled one whenever no fetched or cached manifest
+//     could be used. A verified manifest is returned as-is, even if its model lists are empty.
+//   - error: non-nil only to report why the fallback was used; callers may ignore it.
+func FetchModelCatalog(workingDir, manifestURL string, offline bool, adminPublicKey string) (ModelCatalog, error) {
+	if offline {
+		return BundledModelCatalog, nil
+	}
+	if manifestURL == "" {
+		manifestURL = DefaultModelsManifestURL
+	}
+
+	cachePath := filepath.Join(workingDir, modelsManifestCacheFile)
+	etagPath := filepath.Join(workingDir, modelsManifestETagFile)
+
+	req, err := http.NewRequest(http.MethodGet, manifestURL, nil)
+	if err != nil {
+		return BundledModelCatalog, fmt.Errorf("failed to build manifest request: %w", err)
+	}
+	if etag, err := os.ReadFile(etagPath); err == nil {
+		req.Header.Set("If-None-Match", strings.TrimSpace(string(etag)))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return loadCachedCatalog(cachePath, adminPublicKey, fmt.Errorf("failed to fetch models manifest: %w", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return loadCachedCatalog(cachePath, adminPublicKey, nil)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return loadCachedCatalog(cachePath, adminPublicKey, fmt.Errorf("models manifest request failed with status %d", resp.StatusCode))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return loadCachedCatalog(cachePath, adminPublicKey, fmt.Errorf("failed to read models manifest: %w", err))
+	}
+
+	catalog, err := parseAndVerifyManifest(body, adminPublicKey)
+	if err != nil {
+		return loadCachedCatalog(cachePath, adminPublicKey, err)
+	}
+
+	// cache the verified manifest and its ETag for the next run / offline use
+	os.WriteFile(cachePath, body, 0644)
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		os.WriteFile(etagPath, []byte(etag), 0644)
+	}
+
+	return catalog, nil
+}
+
+// loadCachedCatalog falls back to the on-disk manifest cache, and ultimately
+// to the bundled catalog, when the remote fetch couldn't be used. fetchErr is
+// only used to annotate the returned error for logging; a nil fetchErr means
+// the caller is deliberately reusing a 304 Not Modified response.
+func loadCachedCatalog(cachePath, adminPublicKey string, fetchErr error) (ModelCatalog, error) {
+	body, err := os.ReadFile(cachePath)
+	if err != nil {
+		return BundledModelCatalog, fmt.Errorf("%w (no cached manifest available, using bundled catalog)", fetchErr)
+	}
+
+	catalog, err := parseAndVerifyManifest(body, adminPublicKey)
+	if err != nil {
+		return BundledModelCatalog, fmt.Errorf("cached manifest is invalid, using bundled catalog: %w", err)
+	}
+	return catalog, fetchErr
+}
+
+// parseAndVerifyManifest decodes a manifest payload and, when adminPublicKey
+// decodes to a usable 32-byte Ed25519 key, verifies its signature over the
+// encoded catalog, rejecting the manifest outright on a bad signature. If
+// adminPublicKey itself isn't in a shape we can verify (empty, malformed, or
+// wrong length), the manifest is accepted with a printed warning instead,
+// since that's a launcher misconfiguration rather than evidence of tampering.
+func parseAndVerifyManifest(body []byte, adminPublicKey string) (ModelCatalog, error) {
+	var manifest modelsManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return ModelCatalog{}, fmt.Errorf("failed to parse models manifest: %w", err)
+	}
+
+	payload, err := json.Marshal(manifest.Models)
+	if err != nil {
+		return ModelCatalog{}, fmt.Errorf("failed to re-encode manifest models for verification: %w", err)
+	}
+
+	if adminPublicKey != "" {
+		pubKeyBytes, err := hex.DecodeString(strings.TrimPrefix(adminPublicKey, "0x"))
+		if err != nil || len(pubKeyBytes) != ed25519.PublicKeySize {
+			fmt.Printf("Warning: admin public key is not a usable %d-byte ed25519 key, skipping models manifest signature verification\n", ed25519.PublicKeySize)
+		} else if err := verifyManifestSignature(payload, manifest.Signature, pubKeyBytes); err != nil {
+			return ModelCatalog{}, fmt.Errorf("rejecting models manifest: %w", err)
+		}
+	}
+
+	return manifest.Models, nil
+}
+
+// verifyManifestSignature verifies an Ed25519 signature (hex-encoded) over
+// payload using the already-decoded, already-length-checked pubKeyBytes.
+func verifyManifestSignature(payload []byte, signatureHex string, pubKeyBytes []byte) error {
+	signature, err := hex.DecodeString(signatureHex)
+	if err != nil {
+		return fmt.Errorf("invalid manifest signature encoding: %w", err)
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(pubKeyBytes), payload, signature) {
+		return fmt.Errorf("manifest signature does not match admin public key")
+	}
+	return nil
+}
+
+// ProbeRemoteModel checks that model is actually servable by provider's
+// /models endpoint, catching a typo in a PickModelsFromProfile profile
+// before the compute node fails on its first job instead of at startup. A
+// network error or missing API key is treated as "couldn't verify" rather
+// than "invalid": we accept the model anyway, matching the rest of the
+// launcher's permissive handling of optional integrity checks.
+//
+// Parameters:
+//   - provider: "openai" or "gemini"; any other value is accepted without a probe.
+//   - model: the model name to probe.
+//   - apiKey: the provider's API key, used to authenticate the probe.
+//
+// Returns:
+//   - bool: true if the model is confirmed available, or verification couldn't be completed.
+func ProbeRemoteModel(provider, model, apiKey string) bool {
+	if apiKey == "" {
+		return true
+	}
+
+	var req *http.Request
+	var err error
+	switch provider {
+	case "openai":
+		req, err = http.NewRequest(http.MethodGet, fmt.Sprintf("https://api.openai.com/v1/models/%s", model), nil)
+		if err == nil {
+			req.Header.Set("Authorization", "Bearer "+apiKey)
+		}
+	case "gemini":
+		req, err = http.NewRequest(http.MethodGet, fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s?key=%s", model, apiKey), nil)
+	default:
+		return true
+	}
+	if err != nil {
+		fmt.Printf("Warning: could not build %s model probe for %s, accepting it anyway: %s\n", provider, model, err)
+		return true
+	}
+
+	client := http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Printf("Warning: could not verify %s model %s, accepting it anyway: %s\n", provider, model, err)
+		return true
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode != http.StatusNotFound
+}
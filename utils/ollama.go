@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -59,17 +62,20 @@ func IsOllamaServing(host, port string) bool {
 }
 
 // RunOllamaServe starts the Ollama service on the specified host and port, and checks if it starts successfully.
+// The env it launches with is tuned for accelKind (see DetectAcceleration), so the server actually uses a GPU
+// when one is available instead of silently falling back to CPU.
 //
 // Parameters:
 //   - host: The host address where Ollama should run.
 //   - port: The port number on which Ollama should listen.
+//   - accelKind: The acceleration kind detected by DetectAcceleration (e.g. AccelerationNvidia).
 //
 // Returns:
 //   - int: The process ID (PID) of the Ollama service.
 //   - error: Returns an error if the Ollama service fails to start, otherwise nil.
-func RunOllamaServe(host, port string) (int, error) {
-	ollama_env := fmt.Sprintf("OLLAMA_HOST=%s:%s", host, port)
-	pid, err := RunCommand("", "none", false, 0, []string{ollama_env}, "ollama", "serve")
+func RunOllamaServe(host, port, accelKind string) (int, error) {
+	ollama_env := append([]string{fmt.Sprintf("OLLAMA_HOST=%s:%s", host, port)}, AccelerationEnvVars(accelKind)...)
+	pid, err := RunCommand("", "none", false, 0, ollama_env, "ollama", "serve")
 	if err != nil {
 		return 0, fmt.Errorf("failed during running ollama serve: %w", err)
 	}
@@ -109,13 +115,18 @@ func HandleOllamaEnv(ollamaHost, ollamaPort string) (string, string) {
 			ollamaPort = strconv.Itoa(DEFAULT_OLLAMA_PORT)
 		}
 
+		accelKind, _ := DetectAcceleration()
+		if accelKind == AccelerationCPU {
+			fmt.Println("Warning: no GPU acceleration detected, Ollama will run on CPU. This will be extremely slow for large models.")
+		}
+
 		// check is it already serving
 		if IsOllamaServing(ollamaHost, ollamaPort) {
 			fmt.Printf("Local Ollama is already up at %s:%s and running, using it\n", ollamaHost, ollamaPort)
 		} else {
 			// ollama is not live, so we launch it ourselves
 			fmt.Println("Local Ollama is not live, running ollama serve")
-			ollama_pid, err := RunOllamaServe(ollamaHost, ollamaPort)
+			ollama_pid, err := RunOllamaServe(ollamaHost, ollamaPort, accelKind)
 			if err != nil {
 				// ollama failed to start, exit
 				fmt.Println(err)
@@ -133,3 +144,132 @@ func HandleOllamaEnv(ollamaHost, ollamaPort string) (string, string) {
 
 	return ollamaHost, ollamaPort
 }
+
+// ollamaTagsResponse is the response shape of Ollama's `GET /api/tags`.
+type ollamaTagsResponse struct {
+	Models []struct {
+		Name string `json:"name"`
+	} `json:"models"`
+}
+
+// ollamaPullProgress is a single newline-delimited JSON object streamed by
+// Ollama's `POST /api/pull`.
+type ollamaPullProgress struct {
+	Status    string `json:"status"`
+	Digest    string `json:"digest"`
+	Total     int64  `json:"total"`
+	Completed int64  `json:"completed"`
+}
+
+// listOllamaModels fetches the models already present on an Ollama instance
+// via `GET /api/tags`.
+func listOllamaModels(host, port string) (map[string]bool, error) {
+	client := http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(fmt.Sprintf("%s:%s/api/tags", host, port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ollama models: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list ollama models, status code: %d", resp.StatusCode)
+	}
+
+	var tags ollamaTagsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("failed to parse ollama tags response: %w", err)
+	}
+
+	present := make(map[string]bool, len(tags.Models))
+	for _, model := range tags.Models {
+		present[model.Name] = true
+	}
+	return present, nil
+}
+
+// pullOllamaModel downloads a single model via Ollama's `POST /api/pull`,
+// rendering a simple progress bar from the streamed newline-delimited JSON
+// progress objects.
+func pullOllamaModel(host, port, model string) error {
+	body, err := json.Marshal(map[string]interface{}{"name": model, "stream": true})
+	if err != nil {
+		return fmt.Errorf("failed to build pull request for %s: %w", model, err)
+	}
+
+	client := http.Client{Timeout: 0} // pulls can take a long time, no client-side timeout
+	resp, err := client.Post(fmt.Sprintf("%s:%s/api/pull", host, port), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to start pulling %s: %w", model, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to pull %s, status code: %d", model, resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var progress ollamaPullProgress
+		if err := json.Unmarshal(scanner.Bytes(), &progress); err != nil {
+			continue
+		}
+
+		if progress.Total > 0 {
+			percent := float64(progress.Completed) / float64(progress.Total) * 100
+			fmt.Printf("\r%s: %s (%.1f%%)", model, progress.Status, percent)
+		} else if progress.Status != "" {
+			fmt.Printf("\r%s: %s", model, progress.Status)
+		}
+
+		if progress.Status == "success" {
+			fmt.Println()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed while streaming pull progress for %s: %w", model, err)
+	}
+
+	return nil
+}
+
+// EnsureOllamaModels proactively downloads every model in models that isn't
+// already present on the Ollama instance at host:port, using Ollama's HTTP
+// `POST /api/pull` endpoint instead of shelling out to the `ollama` CLI. This
+// avoids coupling the launcher to the `ollama` binary being on PATH and lets
+// the compute node start without blocking its first inference request on a
+// model download.
+//
+// Models already present (per `GET /api/tags`) are skipped. A failure to
+// pull one model doesn't stop the others; all failures are aggregated into
+// the returned error.
+//
+// Returns:
+//   - error: nil if every requested model is present or was pulled successfully,
+//     otherwise an aggregated error naming every model that failed.
+func EnsureOllamaModels(host, port string, models []string) error {
+	present, err := listOllamaModels(host, port)
+	if err != nil {
+		// if we can't even list what's there, fall back to attempting every pull
+		present = map[string]bool{}
+	}
+
+	var failed []string
+	for _, model := range models {
+		if present[model] {
+			fmt.Printf("Model %s is already present, skipping\n", model)
+			continue
+		}
+
+		fmt.Printf("Pulling model %s...\n", model)
+		if err := pullOllamaModel(host, port, model); err != nil {
+			fmt.Printf("Failed to pull %s: %s\n", model, err)
+			failed = append(failed, model)
+			continue
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to pull %d model(s): %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
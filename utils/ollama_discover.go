@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// OllamaModelInfo describes a model discovered on the local Ollama install,
+// enriched with the metadata PickModels filters on. Family and quantization
+// are parsed from the model's tag (Ollama's own naming convention, e.g.
+// "llama3:8b-instruct-q4_0"); capabilities are looked up from a small table
+// of known model families, since Ollama doesn't expose either as structured
+// metadata over `ollama list`.
+type OllamaModelInfo struct {
+	Name          string
+	Family        string
+	Quantization  string
+	ContextLength int
+	Capabilities  []string
+}
+
+// ollamaVisionFamilies and ollamaToolFamilies list the model name substrings
+// known to support image input / tool calling respectively. This is a
+// maintained allowlist rather than a live capability query, since Ollama
+// doesn't expose either over the CLI or the HTTP API.
+var (
+	ollamaVisionFamilies = []string{"llava", "bakllava", "moondream", "minicpm-v", "llama3.2-vision"}
+	ollamaToolFamilies   = []string{"llama3.1", "llama3.2", "mistral", "mixtral", "qwen2.5", "firefunction", "command-r"}
+)
+
+// DiscoverOllamaModels lists the models installed on the local Ollama by
+// shelling out to `ollama list` and parsing its tab-separated output. It's a
+// local-CLI complement to listOllamaModels (which hits a given host's HTTP
+// API): PickModels runs before we know whether a remote OLLAMA_HOSTS pool
+// will be used, so it can only rely on what's installed locally.
+//
+// Returns:
+//   - []OllamaModelInfo: the discovered models, nil (not an error) if ollama isn't installed.
+//   - error: non-nil only if `ollama list` is available but fails to run.
+func DiscoverOllamaModels() ([]OllamaModelInfo, error) {
+	if !IsCommandAvailable("ollama") {
+		return nil, nil
+	}
+
+	result, err := DefaultCommandRunner.Run(context.Background(), exec.Command("ollama", "list"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to run ollama list: %w", err)
+	}
+
+	var models []OllamaModelInfo
+	for i, line := range strings.Split(result.Stdout.String(), "\n") {
+		if i == 0 || strings.TrimSpace(line) == "" {
+			continue // header row
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		info := newOllamaModelInfo(fields[0])
+		enrichContextLength(&info)
+		models = append(models, info)
+	}
+	return models, nil
+}
+
+// newOllamaModelInfo builds an OllamaModelInfo from a model name, parsing
+// family/quantization out of its tag and looking up known capabilities.
+func newOllamaModelInfo(name string) OllamaModelInfo {
+	info := OllamaModelInfo{Name: name, Family: name}
+
+	if base, tag, found := strings.Cut(name, ":"); found {
+		info.Family = base
+		for _, part := range strings.Split(tag, "-") {
+			if isQuantizationTag(part) {
+				info.Quantization = strings.ToUpper(part)
+			}
+		}
+	}
+
+	for _, vf := range ollamaVisionFamilies {
+		if strings.Contains(name, vf) {
+			info.Capabilities = append(info.Capabilities, "vision")
+			break
+		}
+	}
+	for _, tf := range ollamaToolFamilies {
+		if strings.Contains(name, tf) {
+			info.Capabilities = append(info.Capabilities, "tools")
+			break
+		}
+	}
+
+	return info
+}
+
+// isQuantizationTag reports whether part looks like a quantization suffix
+// (q4_0, q4_k_m, q8_0, fp16, f16, ...).
+func isQuantizationTag(part string) bool {
+	part = strings.ToLower(part)
+	for _, prefix := range []string{"q2", "q3", "q4", "q5", "q6", "q8"} {
+		if strings.HasPrefix(part, prefix) {
+			return true
+		}
+	}
+	return part == "fp16" || part == "f16" || part == "fp32"
+}
+
+// enrichContextLength best-effort fills in info.ContextLength by parsing
+// `ollama show <name> --parameters` for a num_ctx line. Not every model sets
+// num_ctx explicitly, and the command can fail outright, so both cases just
+// leave ContextLength at 0 (no constraint) instead of failing discovery.
+func enrichContextLength(info *OllamaModelInfo) {
+	result, err := DefaultCommandRunner.Run(context.Background(), exec.Command("ollama", "show", info.Name, "--parameters"))
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(result.Stdout.String(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "num_ctx" {
+			if n, err := strconv.Atoi(fields[1]); err == nil {
+				info.ContextLength = n
+			}
+		}
+	}
+}
+
+// OllamaModelFilter narrows DiscoverOllamaModels' results by capability
+// instead of forcing the user to recognize every model name/tag by eye.
+// Zero values are wildcards, so an empty OllamaModelFilter matches everything.
+type OllamaModelFilter struct {
+	MinContextLength int
+	Quantization     string
+	Family           string
+	Capabilities     []string
+}
+
+// Matches reports whether info satisfies every constraint set on f.
+func (f OllamaModelFilter) Matches(info OllamaModelInfo) bool {
+	if f.MinContextLength > 0 && info.ContextLength < f.MinContextLength {
+		return false
+	}
+	if f.Quantization != "" && !strings.EqualFold(f.Quantization, info.Quantization) {
+		return false
+	}
+	if f.Family != "" && !strings.Contains(strings.ToLower(info.Family), strings.ToLower(f.Family)) {
+		return false
+	}
+	for _, capability := range f.Capabilities {
+		if !containsString(info.Capabilities, capability) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterOllamaModels returns the subset of models matching filter.
+func FilterOllamaModels(models []OllamaModelInfo, filter OllamaModelFilter) []OllamaModelInfo {
+	var filtered []OllamaModelInfo
+	for _, m := range models {
+		if filter.Matches(m) {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,232 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OllamaPoolMaxConsecutiveFailures is how many consecutive failed health
+// checks an endpoint tolerates before the pool drops it entirely.
+const OllamaPoolMaxConsecutiveFailures = 3
+
+// OllamaEndpoint tracks everything the pool knows about a single Ollama
+// backend: what it's hosting, how it's been responding, and which group
+// (e.g. a rack or GPU tier) it belongs to.
+type OllamaEndpoint struct {
+	URL       string        `json:"url"`
+	Group     string        `json:"group,omitempty"`
+	Models    []string      `json:"models"`
+	Latency   time.Duration `json:"latency"`
+	LastSeen  time.Time     `json:"last_seen"`
+	Healthy   bool          `json:"healthy"`
+	failCount int
+}
+
+// OllamaFilter narrows down Pick to endpoints in a given group. Narrowing
+// by hosted model is done by Pick's own model argument, not by the filter.
+type OllamaFilter struct {
+	Group string
+}
+
+// OllamaPool is a set of Ollama endpoints, health-checked on an interval, so
+// the compute node can be pointed at a rotating healthy backend instead of a
+// single hard-coded OLLAMA_HOST.
+type OllamaPool struct {
+	mu        sync.RWMutex
+	endpoints map[string]*OllamaEndpoint
+}
+
+// NewOllamaPool builds a pool from a list of endpoint URLs (e.g. parsed from
+// `OLLAMA_HOSTS=http://gpu1:11434,http://gpu2:11434`), all tagged with the
+// given group. Endpoints start out marked unhealthy until the first refresh.
+func NewOllamaPool(endpointURLs []string, group string) *OllamaPool {
+	pool := &OllamaPool{endpoints: make(map[string]*OllamaEndpoint, len(endpointURLs))}
+	for _, url := range endpointURLs {
+		url = strings.TrimSpace(url)
+		if url == "" {
+			continue
+		}
+		pool.endpoints[url] = &OllamaEndpoint{URL: url, Group: group}
+	}
+	return pool
+}
+
+// ParseOllamaHosts splits a comma-separated `OLLAMA_HOSTS` value into a
+// clean slice of endpoint URLs.
+func ParseOllamaHosts(raw string) []string {
+	var hosts []string
+	for _, host := range strings.Split(raw, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// RefreshAll health-checks every endpoint in the pool: a `GET /` for
+// liveness and latency, and a `GET /api/tags` for the models it's hosting.
+// Endpoints that fail OllamaPoolMaxConsecutiveFailures checks in a row are
+// dropped from the pool entirely.
+func (p *OllamaPool) RefreshAll() {
+	p.mu.Lock()
+	endpoints := make([]*OllamaEndpoint, 0, len(p.endpoints))
+	for _, ep := range p.endpoints {
+		endpoints = append(endpoints, ep)
+	}
+	p.mu.Unlock()
+
+	for _, ep := range endpoints {
+		p.refreshOne(ep)
+	}
+}
+
+// refreshOne health-checks a single endpoint and updates (or drops) it.
+func (p *OllamaPool) refreshOne(ep *OllamaEndpoint) {
+	client := http.Client{Timeout: 3 * time.Second}
+
+	started := time.Now()
+	resp, err := client.Get(ep.URL + "/")
+	if err == nil {
+		resp.Body.Close()
+	}
+	latency := time.Since(started)
+
+	if err != nil || resp.StatusCode != http.StatusOK {
+		p.mu.Lock()
+		ep.Healthy = false
+		ep.failCount++
+		drop := ep.failCount >= OllamaPoolMaxConsecutiveFailures
+		if drop {
+			delete(p.endpoints, ep.URL)
+		}
+		p.mu.Unlock()
+		return
+	}
+
+	models, err := fetchEndpointModels(client, ep.URL)
+	if err != nil {
+		fmt.Printf("Warning: could not list models on %s: %s\n", ep.URL, err)
+	}
+
+	p.mu.Lock()
+	ep.Healthy = true
+	ep.failCount = 0
+	ep.Latency = latency
+	ep.LastSeen = time.Now()
+	if models != nil {
+		ep.Models = models
+	}
+	p.mu.Unlock()
+}
+
+// Start launches a background goroutine that calls RefreshAll on the given
+// interval until stop is closed.
+func (p *OllamaPool) Start(interval time.Duration, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				p.RefreshAll()
+			}
+		}
+	}()
+}
+
+// Pick returns a healthy endpoint that hosts model (if model is non-empty)
+// and matches where (if given), preferring the lowest-latency match.
+//
+// Returns:
+//   - string: the chosen endpoint's URL.
+//   - error: if no healthy endpoint matches the given model/filter.
+func (p *OllamaPool) Pick(model string, where *OllamaFilter) (string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var best *OllamaEndpoint
+	for _, ep := range p.endpoints {
+		if !ep.Healthy {
+			continue
+		}
+		if where != nil && where.Group != "" && ep.Group != where.Group {
+			continue
+		}
+		if model != "" && !containsString(ep.Models, model) {
+			continue
+		}
+		if best == nil || ep.Latency < best.Latency {
+			best = ep
+		}
+	}
+
+	if best == nil {
+		return "", fmt.Errorf("no healthy ollama endpoint available for model %q", model)
+	}
+	return best.URL, nil
+}
+
+// Snapshot returns a point-in-time copy of every endpoint currently tracked
+// by the pool, for debugging/status purposes.
+func (p *OllamaPool) Snapshot() []OllamaEndpoint {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	snapshot := make([]OllamaEndpoint, 0, len(p.endpoints))
+	for _, ep := range p.endpoints {
+		snapshot = append(snapshot, *ep)
+	}
+	return snapshot
+}
+
+// ServeStatus starts a small local HTTP endpoint at addr (e.g. "127.0.0.1:7777")
+// that reports the current pool state as JSON, for debugging.
+func (p *OllamaPool) ServeStatus(addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(p.Snapshot())
+	})
+	return http.ListenAndServe(addr, mux)
+}
+
+// fetchEndpointModels fetches the list of model names hosted at endpointURL
+// via `GET /api/tags`, reusing the same response shape as listOllamaModels.
+func fetchEndpointModels(client http.Client, endpointURL string) ([]string, error) {
+	resp, err := client.Get(endpointURL + "/api/tags")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list models, status code: %d", resp.StatusCode)
+	}
+
+	var tags ollamaTagsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("failed to parse tags response: %w", err)
+	}
+
+	models := make([]string, 0, len(tags.Models))
+	for _, model := range tags.Models {
+		models = append(models, model.Name)
+	}
+	return models, nil
+}
+
+// containsString reports whether needle is present in haystack.
+func containsString(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
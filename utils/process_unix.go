@@ -4,8 +4,10 @@
 package utils
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/exec"
 	"syscall"
 	"time"
 )
@@ -22,25 +24,104 @@ func IsProcessRunning(pid int) bool {
 	return err == nil
 }
 
-// StopProcess stops a process by its PID on Unix-based systems.
-func StopProcess(pid int) error {
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return fmt.Errorf("could not find process: %w", err)
+// stopProcessTimeout bounds how long StopProcessCtx waits for a process to
+// exit after SIGKILL before giving up.
+const stopProcessTimeout = 30 * time.Second
+
+// shutdownSignals are the signals DeathWatcher.Watch listens for on Unix.
+var shutdownSignals = []os.Signal{syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP}
+
+// forceKillGroup immediately SIGKILLs pid's whole process group, skipping
+// any grace period. Used by DeathWatcher's double-signal escalation path.
+func forceKillGroup(pid int) {
+	if pgid, err := syscall.Getpgid(pid); err == nil {
+		syscall.Kill(-pgid, syscall.SIGKILL)
+		return
 	}
+	syscall.Kill(pid, syscall.SIGKILL)
+}
 
-	// Send SIGTERM (soft termination)
-	err = process.Signal(syscall.SIGTERM)
-	if err != nil {
+// setProcessGroup runs cmd in its own process group (setpgid), so
+// StopProcessCtx can later signal the whole group and reap any child
+// workers the process spawned instead of leaking them.
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// StopProcess stops pid the same way a container shim would: SIGTERM, a
+// 10-second grace period, then SIGKILL, signaling pid's whole process group
+// so child workers it spawned (e.g. via RunCommand, which sets up the group
+// via setProcessGroup) are reaped too. Use StopProcessCtx directly to
+// customize the grace period or disable group signaling.
+func StopProcess(pid int) error {
+	return StopProcessCtx(context.Background(), pid, 10*time.Second, true)
+}
+
+// StopProcessCtx stops pid, escalating from SIGTERM to SIGKILL if it hasn't
+// exited within gracePeriod. When killGroup is true, signals are sent to
+// pid's whole process group (-pgid) instead of just pid, so dependent
+// processes it spawned don't outlive it. ctx bounds both waits: once ctx is
+// done, the grace wait ends early (escalating straight to SIGKILL) and the
+// wait after SIGKILL gives up immediately.
+func StopProcessCtx(ctx context.Context, pid int, gracePeriod time.Duration, killGroup bool) error {
+	pgid, pgidErr := syscall.Getpgid(pid)
+	canSignalGroup := killGroup && pgidErr == nil
+
+	if err := signalProcess(pid, pgid, canSignalGroup, syscall.SIGTERM); err != nil {
 		return fmt.Errorf("could not terminate process: %w", err)
 	}
+	if waitForExit(ctx, pid, gracePeriod) {
+		return nil
+	}
 
-	// termination might take some time and it will effect the next steps during update, sleep 5 seconds just in case
-	time.Sleep(5 * time.Second)
+	fmt.Printf("Process %d did not exit within %s after SIGTERM, sending SIGKILL\n", pid, gracePeriod)
+	if err := signalProcess(pid, pgid, canSignalGroup, syscall.SIGKILL); err != nil {
+		return fmt.Errorf("could not kill process: %w", err)
+	}
+	if waitForExit(ctx, pid, stopProcessTimeout) {
+		return nil
+	}
+
+	return fmt.Errorf("process %d did not exit within %s after SIGKILL", pid, stopProcessTimeout)
+}
 
+// signalProcess sends sig to pid's whole process group (-pgid) when
+// canSignalGroup is set, otherwise to pid alone. ESRCH (no such process) is
+// treated as already-exited rather than an error.
+func signalProcess(pid, pgid int, canSignalGroup bool, sig syscall.Signal) error {
+	if canSignalGroup {
+		if err := syscall.Kill(-pgid, sig); err != nil && err != syscall.ESRCH {
+			return err
+		}
+		return nil
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	if err := process.Signal(sig); err != nil && err != syscall.ESRCH {
+		return err
+	}
 	return nil
 }
 
+// waitForExit polls IsProcessRunning until pid exits, timeout elapses, or
+// ctx is done, returning true only if pid actually exited.
+func waitForExit(ctx context.Context, pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for IsProcessRunning(pid) {
+		if time.Now().After(deadline) || ctx.Err() != nil {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return true
+}
+
 func SetFileDescriptorLimit(limit uint64) error {
 	var rLimit syscall.Rlimit
 	rLimit.Max = limit
@@ -4,11 +4,26 @@
 package utils
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
 	"syscall"
 	"time"
 )
 
+// shutdownSignals is just os.Interrupt on Windows: SIGTERM/SIGHUP aren't
+// real Windows console signals, and os.Interrupt is what Go translates a
+// Ctrl-C (CTRL_C_EVENT) to.
+var shutdownSignals = []os.Signal{os.Interrupt}
+
+// forceKillGroup immediately force-kills pid's whole process tree, skipping
+// any grace period. Used by DeathWatcher's double-signal escalation path.
+func forceKillGroup(pid int) {
+	taskkill(pid, true, true)
+}
+
 // IsProcessRunning checks if a process with the given PID is running on Windows.
 func IsProcessRunning(pid int) bool {
 	handle, err := syscall.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(pid))
@@ -19,22 +34,71 @@ func IsProcessRunning(pid int) bool {
 	return true
 }
 
-// StopProcess stops a process by its PID on Windows.
+// stopProcessTimeout bounds how long StopProcessCtx waits for a process to
+// exit after a forceful kill before giving up.
+const stopProcessTimeout = 30 * time.Second
+
+// setProcessGroup is a no-op on Windows: there's no setpgid equivalent, and
+// StopProcessCtx instead reaps dependent processes via `taskkill /T`, which
+// walks the process tree by parent PID rather than a process group.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// StopProcess stops pid the same way a container shim would: a graceful
+// close, a 10-second grace period, then a forceful kill, reaping pid's whole
+// process tree (via `taskkill /T`) so child workers it spawned are cleaned
+// up too. Use StopProcessCtx directly to customize the grace period or
+// disable tree cleanup.
 func StopProcess(pid int) error {
-	handle, err := syscall.OpenProcess(syscall.PROCESS_TERMINATE, false, uint32(pid))
-	if err != nil {
-		return fmt.Errorf("could not open process: %w", err)
-	}
-	defer syscall.CloseHandle(handle)
+	return StopProcessCtx(context.Background(), pid, 10*time.Second, true)
+}
 
-	// Terminate the process with an exit code of 1
-	err = syscall.TerminateProcess(handle, 1)
-	if err != nil {
+// StopProcessCtx stops pid, escalating from a graceful `taskkill` to a
+// forceful `taskkill /F` if it hasn't exited within gracePeriod. When
+// killGroup is true, /T is added so pid's whole descendant process tree is
+// asked to close as well. ctx bounds both waits: once ctx is done, the grace
+// wait ends early (escalating straight to `taskkill /F`) and the wait after
+// the forceful kill gives up immediately.
+func StopProcessCtx(ctx context.Context, pid int, gracePeriod time.Duration, killGroup bool) error {
+	if err := taskkill(pid, killGroup, false); err != nil {
 		return fmt.Errorf("could not terminate process: %w", err)
 	}
+	if waitForExit(ctx, pid, gracePeriod) {
+		return nil
+	}
 
-	// in windows termination might take some time and it will affect the next steps during update, sleep 5 seconds just in case
-	time.Sleep(5 * time.Second)
+	fmt.Printf("Process %d did not exit within %s, forcing termination\n", pid, gracePeriod)
+	if err := taskkill(pid, killGroup, true); err != nil {
+		return fmt.Errorf("could not kill process: %w", err)
+	}
+	if waitForExit(ctx, pid, stopProcessTimeout) {
+		return nil
+	}
+
+	return fmt.Errorf("process %d did not exit within %s after forced termination", pid, stopProcessTimeout)
+}
+
+// taskkill shells out to Windows' taskkill to stop pid, optionally its whole
+// descendant process tree (/T) and/or forcefully (/F).
+func taskkill(pid int, tree, force bool) error {
+	args := []string{"/PID", strconv.Itoa(pid)}
+	if tree {
+		args = append(args, "/T")
+	}
+	if force {
+		args = append(args, "/F")
+	}
+	return exec.Command("taskkill", args...).Run()
+}
 
-	return nil
+// waitForExit polls IsProcessRunning until pid exits, timeout elapses, or
+// ctx is done, returning true only if pid actually exited.
+func waitForExit(ctx context.Context, pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for IsProcessRunning(pid) {
+		if time.Now().After(deadline) || ctx.Err() != nil {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return true
 }
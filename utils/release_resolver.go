@@ -0,0 +1,212 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+const (
+	releaseResolverCacheFile = "compute-releases.json"
+	releaseResolverETagFile  = "compute-releases.etag"
+	releaseResolverTagsURL   = "https://api.github.com/repos/firstbatchxyz/dkn-compute-node/tags"
+)
+
+// ReleaseResolver resolves dkn-compute-node release tags against semver
+// constraints and release channels, replacing the lexical tag-order
+// assumptions that GetComputeLatestTag used to rely on.
+type ReleaseResolver struct {
+	workingDir  string
+	githubToken string
+}
+
+// NewReleaseResolver builds a resolver that caches its tag list under
+// workingDir and authenticates with the GITHUB_TOKEN env var (if set) for a
+// 5000/hr GitHub API rate limit instead of the anonymous 60/hr.
+func NewReleaseResolver(workingDir string) *ReleaseResolver {
+	return &ReleaseResolver{workingDir: workingDir, githubToken: os.Getenv("GITHUB_TOKEN")}
+}
+
+// releaseTag pairs a parsed semver.Version with the original tag string.
+type releaseTag struct {
+	raw     string
+	version *semver.Version
+}
+
+// fetchTags returns every semver-parseable tag on the dkn-compute-node repo,
+// sorted newest-first, using a disk cache with an ETag-conditional refetch
+// so repeated launcher runs don't burn through the GitHub rate limit.
+func (r *ReleaseResolver) fetchTags() ([]releaseTag, error) {
+	cachePath := filepath.Join(r.workingDir, releaseResolverCacheFile)
+	etagPath := filepath.Join(r.workingDir, releaseResolverETagFile)
+
+	req, err := http.NewRequest(http.MethodGet, releaseResolverTagsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tags request: %w", err)
+	}
+	if r.githubToken != "" {
+		req.Header.Set("Authorization", "Bearer "+r.githubToken)
+	}
+	if etag, err := os.ReadFile(etagPath); err == nil {
+		req.Header.Set("If-None-Match", strings.TrimSpace(string(etag)))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return r.loadCachedTags(cachePath, fmt.Errorf("failed to fetch release tags: %w", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return r.loadCachedTags(cachePath, nil)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return r.loadCachedTags(cachePath, fmt.Errorf("tags request failed with status %d (rate limited? set GITHUB_TOKEN)", resp.StatusCode))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return r.loadCachedTags(cachePath, fmt.Errorf("failed to read tags response: %w", err))
+	}
+
+	os.WriteFile(cachePath, body, 0644)
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		os.WriteFile(etagPath, []byte(etag), 0644)
+	}
+
+	return parseTagsResponse(body)
+}
+
+// loadCachedTags falls back to the on-disk tag cache when the live fetch
+// can't be used (network error, rate limit, or a 304 Not Modified).
+func (r *ReleaseResolver) loadCachedTags(cachePath string, fetchErr error) ([]releaseTag, error) {
+	body, err := os.ReadFile(cachePath)
+	if err != nil {
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+		return nil, fmt.Errorf("no cached release tags available")
+	}
+	return parseTagsResponse(body)
+}
+
+// parseTagsResponse parses the GitHub tags API response, silently skipping
+// any tag name that isn't valid semver (e.g. stray non-release branches).
+func parseTagsResponse(body []byte) ([]releaseTag, error) {
+	var raw []map[string]interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse tags response: %w", err)
+	}
+
+	tags := make([]releaseTag, 0, len(raw))
+	for _, entry := range raw {
+		name, ok := entry["name"].(string)
+		if !ok {
+			continue
+		}
+		v, err := semver.NewVersion(name)
+		if err != nil {
+			continue
+		}
+		tags = append(tags, releaseTag{raw: name, version: v})
+	}
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("no valid semver tags found")
+	}
+
+	sort.Slice(tags, func(i, j int) bool { return tags[i].version.GreaterThan(tags[j].version) })
+	return tags, nil
+}
+
+// matchesChannel reports whether a release belongs to channel: "stable" (no
+// pre-release component, the default), "dev" (pre-release identifier
+// contains "dev"), "pre" (any pre-release), or an arbitrary semver
+// constraint such as "^0.2" or "~0.2.4".
+func matchesChannel(v *semver.Version, channel string) (bool, error) {
+	switch channel {
+	case "", "stable":
+		return v.Prerelease() == "", nil
+	case "dev":
+		return strings.Contains(v.Prerelease(), "dev"), nil
+	case "pre":
+		return v.Prerelease() != "", nil
+	default:
+		constraint, err := semver.NewConstraint(channel)
+		if err != nil {
+			return false, fmt.Errorf("invalid channel/constraint %q: %w", channel, err)
+		}
+		return constraint.Check(v), nil
+	}
+}
+
+// Latest returns the newest release tag matching channel.
+//
+// Returns:
+//   - string: the matching tag name (e.g. "v0.2.4").
+//   - error: if tags can't be fetched, or none match the channel.
+func (r *ReleaseResolver) Latest(channel string) (string, error) {
+	tags, err := r.fetchTags()
+	if err != nil {
+		return "", err
+	}
+	for _, tag := range tags {
+		ok, err := matchesChannel(tag.version, channel)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return tag.raw, nil
+		}
+	}
+	return "", fmt.Errorf("no release found matching channel %q", channel)
+}
+
+// Previous returns the second-newest release tag matching channel, i.e. the
+// one before Latest(channel). Used as a fallback when the newest release's
+// binaries aren't finished building yet.
+func (r *ReleaseResolver) Previous(channel string) (string, error) {
+	tags, err := r.fetchTags()
+	if err != nil {
+		return "", err
+	}
+	matchCount := 0
+	for _, tag := range tags {
+		ok, err := matchesChannel(tag.version, channel)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			matchCount++
+			if matchCount == 2 {
+				return tag.raw, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no previous release found matching channel %q", channel)
+}
+
+// Resolve returns the newest release tag satisfying an arbitrary semver
+// constraint (e.g. "^0.2", "~0.2.4", ">=0.2.0, <0.3.0").
+func (r *ReleaseResolver) Resolve(constraint string) (string, error) {
+	tags, err := r.fetchTags()
+	if err != nil {
+		return "", err
+	}
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return "", fmt.Errorf("invalid constraint %q: %w", constraint, err)
+	}
+	for _, tag := range tags {
+		if c.Check(tag.version) {
+			return tag.raw, nil
+		}
+	}
+	return "", fmt.Errorf("no release satisfies constraint %q", constraint)
+}
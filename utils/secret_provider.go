@@ -0,0 +1,332 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+// keyringService/keyringUser identify the DKN Wallet Secret Key entry within
+// the OS keyring (macOS Keychain, Windows Credential Manager, or libsecret).
+const (
+	keyringService = "dkn-compute-launcher"
+	keyringUser    = "dkn-wallet-secret-key"
+)
+
+// secretKeyFileName is the encrypted-file SecretProvider's default file,
+// relative to the launcher's working directory.
+const secretKeyFileName = ".dkn_secret.enc"
+
+// scrypt cost parameters for deriving the file-encryption key from a
+// passphrase. N=2^15 keeps a single unlock under ~100ms while still being
+// expensive to brute-force offline.
+const (
+	scryptN       = 1 << 15
+	scryptR       = 8
+	scryptP       = 1
+	scryptKeyLen  = 32
+	scryptSaltLen = 16
+)
+
+// SecretProvider resolves the DKN Wallet Secret Key from a single backend and
+// can persist a newly entered key back to that backend via Rotate. Concrete
+// implementations back onto the OS keyring, an environment variable, an
+// scrypt+AES-GCM encrypted file, or a masked stdin prompt.
+type SecretProvider interface {
+	// Name identifies the provider, e.g. "keyring", for logging and for
+	// matching against the --secret-source flag.
+	Name() string
+
+	// Get returns the stored secret key, or an error if this provider has
+	// none available.
+	Get() (string, error)
+
+	// Rotate prompts for a new secret key (masked, via stdin) and persists
+	// it to this provider's backend, returning the new key.
+	Rotate() (string, error)
+}
+
+// NewSecretProvider constructs the SecretProvider named by source, one of
+// "keyring", "env", "file", or "stdin". workingDir is used by the "file"
+// provider to locate its encrypted secret file.
+//
+// Parameters:
+//   - source: the provider name.
+//   - workingDir: working directory, used by the file provider.
+//
+// Returns:
+//   - SecretProvider: the constructed provider.
+//   - error: non-nil if source doesn't name a known provider.
+func NewSecretProvider(source, workingDir string) (SecretProvider, error) {
+	switch source {
+	case "keyring":
+		return &keyringSecretProvider{}, nil
+	case "env":
+		return &envSecretProvider{}, nil
+	case "file":
+		return &fileSecretProvider{path: filepath.Join(workingDir, secretKeyFileName)}, nil
+	case "stdin":
+		return &stdinSecretProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unknown secret source %q, must be one of: keyring, env, file, stdin", source)
+	}
+}
+
+// defaultSecretSourceOrder is the order providers are tried in when the
+// user doesn't pin one via --secret-source: cheapest/least-intrusive first,
+// falling back to the masked stdin prompt so a key is always obtainable.
+var defaultSecretSourceOrder = []string{"env", "keyring", "file", "stdin"}
+
+// ResolveDknSecretKey returns the DKN Wallet Secret Key using source if
+// given (one of keyring/env/file/stdin), or by trying
+// defaultSecretSourceOrder in turn otherwise and returning the first key a
+// provider produces. The stdin provider, tried last, can still fail (an
+// invalid key, or a terminal that can't be read), so an error is possible
+// even without a pinned source.
+//
+// Parameters:
+//   - source: the --secret-source flag value, or "" to use the default order.
+//   - workingDir: working directory, used by the file provider.
+//
+// Returns:
+//   - string: the validated DKN Wallet Secret Key.
+//   - error: non-nil if source is invalid, if the pinned provider fails, or if
+//     no provider in the default order produced a key.
+func ResolveDknSecretKey(source, workingDir string) (string, error) {
+	if source != "" {
+		provider, err := NewSecretProvider(source, workingDir)
+		if err != nil {
+			return "", err
+		}
+		return provider.Get()
+	}
+
+	for _, name := range defaultSecretSourceOrder {
+		provider, err := NewSecretProvider(name, workingDir)
+		if err != nil {
+			return "", err
+		}
+		skey, err := provider.Get()
+		if err == nil {
+			return skey, nil
+		}
+	}
+
+	return "", fmt.Errorf("no secret provider could produce a DKN Wallet Secret Key")
+}
+
+// validateSecretKey trims an optional "0x" prefix and checks that raw is
+// 32-bytes hex encoded, returning the normalized (prefix-stripped) key.
+func validateSecretKey(raw string) (string, error) {
+	skey := strings.TrimPrefix(strings.TrimSpace(raw), "0x")
+	decoded, err := hex.DecodeString(skey)
+	if err != nil {
+		return "", fmt.Errorf("DKN Wallet Secret Key should be 32-bytes hex encoded")
+	}
+	if len(decoded) != 32 {
+		return "", fmt.Errorf("DKN Wallet Secret Key should be 32 bytes long")
+	}
+	return skey, nil
+}
+
+// GetDknSecretKey prompts the user to enter their DKN Wallet Secret Key
+// (input masked, not echoed), validates it, and returns it. It's the stdin
+// SecretProvider's Get, kept as a package-level function since it's also the
+// prompt used by the keyring and file providers' Rotate (the env provider
+// can't rotate).
+//
+// Returns:
+//   - string: the validated DKN Wallet Secret Key.
+//   - error: returns an error if the key is not 32-bytes hex encoded, or if
+//     reading from the terminal fails.
+func GetDknSecretKey() (string, error) {
+	fmt.Print("Please enter your DKN Wallet Secret Key (32-bytes hex encoded, input hidden): ")
+	raw, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return "", fmt.Errorf("could not read secret key from terminal: %w", err)
+	}
+	return validateSecretKey(string(raw))
+}
+
+// stdinSecretProvider prompts for the secret key on every Get, masking
+// input via term.ReadPassword instead of echoing it to the terminal.
+type stdinSecretProvider struct{}
+
+func (p *stdinSecretProvider) Name() string { return "stdin" }
+
+func (p *stdinSecretProvider) Get() (string, error) {
+	return GetDknSecretKey()
+}
+
+func (p *stdinSecretProvider) Rotate() (string, error) {
+	return GetDknSecretKey()
+}
+
+// envSecretProvider reads the secret key from DKN_WALLET_SECRET_KEY.
+type envSecretProvider struct{}
+
+func (p *envSecretProvider) Name() string { return "env" }
+
+func (p *envSecretProvider) Get() (string, error) {
+	raw := os.Getenv("DKN_WALLET_SECRET_KEY")
+	if raw == "" {
+		return "", fmt.Errorf("DKN_WALLET_SECRET_KEY is not set")
+	}
+	return validateSecretKey(raw)
+}
+
+func (p *envSecretProvider) Rotate() (string, error) {
+	return "", fmt.Errorf("cannot rotate an env-sourced secret key; set DKN_WALLET_SECRET_KEY yourself")
+}
+
+// keyringSecretProvider stores the secret key in the OS keyring: macOS
+// Keychain, Windows Credential Manager, or libsecret on Linux.
+type keyringSecretProvider struct{}
+
+func (p *keyringSecretProvider) Name() string { return "keyring" }
+
+func (p *keyringSecretProvider) Get() (string, error) {
+	raw, err := keyring.Get(keyringService, keyringUser)
+	if err != nil {
+		return "", fmt.Errorf("could not read secret key from OS keyring: %w", err)
+	}
+	return validateSecretKey(raw)
+}
+
+func (p *keyringSecretProvider) Rotate() (string, error) {
+	skey, err := GetDknSecretKey()
+	if err != nil {
+		return "", err
+	}
+	if err := keyring.Set(keyringService, keyringUser, skey); err != nil {
+		return "", fmt.Errorf("could not store secret key in OS keyring: %w", err)
+	}
+	return skey, nil
+}
+
+// fileSecretProvider stores the secret key in an scrypt+AES-GCM encrypted
+// file at path, protected by a passphrase prompted on stdin.
+type fileSecretProvider struct {
+	path string
+}
+
+func (p *fileSecretProvider) Name() string { return "file" }
+
+func (p *fileSecretProvider) Get() (string, error) {
+	ciphertext, err := os.ReadFile(p.path)
+	if err != nil {
+		return "", fmt.Errorf("could not read encrypted secret key file: %w", err)
+	}
+
+	fmt.Printf("Please enter the passphrase for %s (input hidden): ", p.path)
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return "", fmt.Errorf("could not read passphrase from terminal: %w", err)
+	}
+
+	raw, err := decryptSecretFile(ciphertext, passphrase)
+	if err != nil {
+		return "", err
+	}
+	return validateSecretKey(raw)
+}
+
+func (p *fileSecretProvider) Rotate() (string, error) {
+	skey, err := GetDknSecretKey()
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Printf("Please choose a passphrase to encrypt %s (input hidden): ", p.path)
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return "", fmt.Errorf("could not read passphrase from terminal: %w", err)
+	}
+
+	ciphertext, err := encryptSecretFile(skey, passphrase)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(p.path, ciphertext, 0600); err != nil {
+		return "", fmt.Errorf("could not write encrypted secret key file: %w", err)
+	}
+	return skey, nil
+}
+
+// encryptSecretFile encrypts plaintext with a key derived from passphrase
+// via scrypt, and returns salt || nonce || ciphertext, all that's needed to
+// decrypt it again given the same passphrase.
+func encryptSecretFile(plaintext string, passphrase []byte) ([]byte, error) {
+	salt := make([]byte, scryptSaltLen)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("could not generate salt: %w", err)
+	}
+
+	gcm, err := newSecretFileGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("could not generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nil, nonce, []byte(plaintext), nil)
+	out := append(salt, nonce...)
+	out = append(out, sealed...)
+	return out, nil
+}
+
+// decryptSecretFile reverses encryptSecretFile, deriving the same key from
+// passphrase and the salt embedded in ciphertext.
+func decryptSecretFile(ciphertext []byte, passphrase []byte) (string, error) {
+	if len(ciphertext) < scryptSaltLen {
+		return "", fmt.Errorf("encrypted secret key file is corrupt")
+	}
+	salt := ciphertext[:scryptSaltLen]
+	rest := ciphertext[scryptSaltLen:]
+
+	gcm, err := newSecretFileGCM(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+
+	if len(rest) < gcm.NonceSize() {
+		return "", fmt.Errorf("encrypted secret key file is corrupt")
+	}
+	nonce, sealed := rest[:gcm.NonceSize()], rest[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", fmt.Errorf("could not decrypt secret key file, wrong passphrase?: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// newSecretFileGCM derives an AES-256 key from passphrase and salt via
+// scrypt and returns an AES-GCM cipher.AEAD built on it.
+func newSecretFileGCM(passphrase, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("could not derive encryption key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("could not create AES cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
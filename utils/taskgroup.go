@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// TaskConfig describes a single compute-node instance within a task group:
+// its own model set, log level, provider API keys and Ollama endpoint.
+type TaskConfig struct {
+	Name             string `toml:"name"`
+	Models           string `toml:"models"`
+	LogLevel         string `toml:"log_level"`
+	OpenAIAPIKey     string `toml:"openai_api_key"`
+	GeminiAPIKey     string `toml:"gemini_api_key"`
+	OpenRouterAPIKey string `toml:"openrouter_api_key"`
+	OllamaHost       string `toml:"ollama_host"`
+	OllamaPort       string `toml:"ollama_port"`
+}
+
+// TaskGroupConfig is the root of a `--group` config file, describing every
+// dkn-compute task to launch in parallel from a single launcher process.
+type TaskGroupConfig struct {
+	Tasks []TaskConfig `toml:"task"`
+}
+
+// LoadTaskGroupConfig reads and parses a TOML task-group config file.
+//
+// Returns:
+//   - *TaskGroupConfig: the parsed config, guaranteed to have at least one named task.
+//   - error: if the file cannot be read/parsed, or defines no tasks.
+func LoadTaskGroupConfig(path string) (*TaskGroupConfig, error) {
+	var cfg TaskGroupConfig
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse task group config %q: %w", path, err)
+	}
+	if len(cfg.Tasks) == 0 {
+		return nil, fmt.Errorf("task group config %q defines no tasks", path)
+	}
+	for i, task := range cfg.Tasks {
+		if task.Name == "" {
+			return nil, fmt.Errorf("task #%d is missing a name", i)
+		}
+	}
+	return &cfg, nil
+}
+
+// taskRuntime tracks the running state of a single task within a group.
+type taskRuntime struct {
+	task   TaskConfig
+	pid    int
+	cancel context.CancelFunc
+}
+
+// RunTaskGroup starts every task in cfg in parallel under execCommand, each
+// with its own monitoring goroutine and log file ("logs-<name>.txt"), and
+// shares the version-check/upgrade cycle across the whole group: when a new
+// dkn-compute version is detected, every task is stopped and restarted
+// together on the new binary.
+//
+// Parameters:
+//   - workingDir: directory the tasks run in and the binary is resolved from.
+//   - execCommand: the dkn-compute executable to run (same for every task).
+//   - computeBinary: file name of the dkn-compute binary, swapped during upgrades.
+//   - computeVersion: the currently installed dkn-compute version.
+//   - cfg: the parsed task group config.
+//   - envFor: builds the environment variables for a single task.
+//   - verifyDownloads: whether upgrade downloads are checked against their
+//     published checksum/signature, mirroring --insecure-skip-verify.
+//
+// This function blocks until a task fails to start or an upgrade step
+// (stopping a task, deleting or renaming the binary) fails, and returns that
+// error, mirroring the single-process update loop in main's foreground mode,
+// but fanned out across every task in the group.
+func RunTaskGroup(workingDir, execCommand, computeBinary, computeVersion string, cfg *TaskGroupConfig, envFor func(TaskConfig) []string, verifyDownloads bool) error {
+	for {
+		runtimes := make([]*taskRuntime, 0, len(cfg.Tasks))
+		for _, task := range cfg.Tasks {
+			rt, err := startTask(workingDir, execCommand, task, envFor(task))
+			if err != nil {
+				return fmt.Errorf("failed to start task %q: %w", task.Name, err)
+			}
+			runtimes = append(runtimes, rt)
+			fmt.Printf("Task %q started with PID: %d (logs: logs-%s.txt)\n", task.Name, rt.pid, task.Name)
+		}
+
+		for {
+			time.Sleep(60 * time.Minute)
+			newVersionAvailable, newVersion := IsNewVersionAvaliable(computeVersion)
+			if !newVersionAvailable {
+				continue
+			}
+
+			fmt.Printf("A new compute-node version detected (%s), restarting the whole task group...\n", newVersion)
+			newBinaryTempName := fmt.Sprintf("temp-%s", computeBinary)
+			if err := DownloadLatestComputeBinary(newVersion, workingDir, newBinaryTempName, verifyDownloads); err != nil {
+				fmt.Printf("Error during downloading the latest dkn-compute binary %s\nWill continue running and check again in an hour\n", err)
+				continue
+			}
+
+			for _, rt := range runtimes {
+				rt.cancel()
+				if err := StopProcess(rt.pid); err != nil {
+					return fmt.Errorf("failed to stop task %q during upgrade: %w", rt.task.Name, err)
+				}
+			}
+
+			if err := DeleteFile(workingDir, computeBinary); err != nil {
+				return fmt.Errorf("failed to delete old binary during upgrade: %w", err)
+			}
+			if err := RenameFile(workingDir, newBinaryTempName, computeBinary); err != nil {
+				return fmt.Errorf("failed to install new binary during upgrade: %w", err)
+			}
+
+			computeVersion = newVersion
+			break
+		}
+	}
+}
+
+// startTask launches a single task's dkn-compute process, logging to its own
+// "logs-<name>.txt" file, and starts a monitoring goroutine that exits the
+// launcher if the task's process disappears unexpectedly.
+func startTask(workingDir, execCommand string, task TaskConfig, env []string) (*taskRuntime, error) {
+	pid, err := RunCommand(workingDir, fmt.Sprintf("file:logs-%s.txt", task.Name), false, 0, env, execCommand)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rt := &taskRuntime{task: task, pid: pid, cancel: cancel}
+
+	go func() {
+		for {
+			time.Sleep(5 * time.Second)
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				if !IsProcessRunning(pid) {
+					fmt.Printf("Task %q (PID %d) exited, exiting the launcher\n", task.Name, pid)
+					ExitWithDelay(0)
+				}
+			}
+		}
+	}()
+
+	return rt, nil
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce collapses the burst of events most editors/fsnotify emit for
+// a single logical save (e.g. write + chmod) into one callback invocation.
+const watchDebounce = 500 * time.Millisecond
+
+// WatchConfigFiles watches the given files (e.g. ".env", "models.toml") for
+// writes and invokes onChange with the path of whichever file changed.
+// Events for the same file arriving within watchDebounce of each other are
+// collapsed into a single call. Only files that exist are watched; missing
+// ones (e.g. an optional models.toml) are skipped silently.
+//
+// Returns:
+//   - *fsnotify.Watcher: the underlying watcher, owned by the caller and
+//     closed when done watching.
+//   - error: if the watcher cannot be created or an existing path cannot be
+//     added to it. No error is returned when none of the paths exist.
+func WatchConfigFiles(paths []string, onChange func(path string)) (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		if !FileExists(path) {
+			continue
+		}
+		if err := watcher.Add(path); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+	}
+
+	go func() {
+		lastFired := make(map[string]time.Time)
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+				if since, seen := lastFired[event.Name]; seen && time.Since(since) < watchDebounce {
+					continue
+				}
+				lastFired[event.Name] = time.Now()
+				onChange(event.Name)
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return watcher, nil
+}